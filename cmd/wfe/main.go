@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/ddahon/workfromearth/internal/cli"
+)
+
+func main() {
+	if err := cli.Run(os.Args[1:]); err != nil {
+		if errors.Is(err, cli.ErrUsage) {
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
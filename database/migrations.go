@@ -0,0 +1,16 @@
+// Package database embeds the SQL migrations so binaries can migrate the
+// database without the migration files or sqlite3 on the host.
+package database
+
+import "embed"
+
+// Migrations holds the SQLite NNNN_name.sql files, applied in order, and
+// optional NNNN_name.down.sql files reverting them
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// PostgresMigrations holds the PostgreSQL migrations, named the same way
+//
+//go:embed postgres/*.sql
+var PostgresMigrations embed.FS
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_company_id;
+
+ALTER TABLE jobs DROP COLUMN company_id;
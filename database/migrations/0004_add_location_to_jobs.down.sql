@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_location;
+
+ALTER TABLE jobs DROP COLUMN location;
@@ -0,0 +1,3 @@
+-- SQLite migration: Allow companies to be disabled without deleting them
+
+ALTER TABLE companies ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- SQLite migration: Create company_submissions table
+-- Companies suggested by visitors, waiting for an admin to approve or reject them
+
+CREATE TABLE IF NOT EXISTS company_submissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT,
+    site_url TEXT,
+    careers_url TEXT NOT NULL,
+    ats_type TEXT,
+    ats_url TEXT,
+    submitter_email TEXT,
+    notes TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    company_id INTEGER,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_submissions_status ON company_submissions(status);
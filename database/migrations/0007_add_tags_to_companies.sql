@@ -0,0 +1,3 @@
+-- SQLite migration: Add free-form tags to companies, stored comma-separated
+
+ALTER TABLE companies ADD COLUMN tags TEXT;
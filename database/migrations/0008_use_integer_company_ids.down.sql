@@ -0,0 +1,48 @@
+CREATE TABLE companies_old (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    site_url TEXT,
+    careers_url TEXT,
+    ats_type TEXT,
+    ats_url TEXT,
+    disabled INTEGER NOT NULL DEFAULT 0,
+    tags TEXT,
+    scraped_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+INSERT INTO companies_old (rowid, id, name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, created_at, updated_at)
+SELECT id, CAST(id AS TEXT), name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, created_at, updated_at
+FROM companies;
+
+CREATE TABLE jobs_old (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    company TEXT NOT NULL,
+    description TEXT,
+    job_url TEXT NOT NULL UNIQUE,
+    salary_range TEXT,
+    published_at TEXT,
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    company_id TEXT,
+    location TEXT
+);
+
+INSERT INTO jobs_old (id, title, company, description, job_url, salary_range, published_at, updated_at, created_at, company_id, location)
+SELECT id, title, company, description, job_url, salary_range, published_at, updated_at, created_at, CAST(company_id AS TEXT), location
+FROM jobs;
+
+DROP TABLE jobs;
+DROP TABLE companies;
+ALTER TABLE companies_old RENAME TO companies;
+ALTER TABLE jobs_old RENAME TO jobs;
+
+CREATE INDEX IF NOT EXISTS idx_companies_ats_type ON companies(ats_type);
+CREATE INDEX IF NOT EXISTS idx_companies_name ON companies(name);
+CREATE INDEX IF NOT EXISTS idx_jobs_company ON jobs(company);
+CREATE INDEX IF NOT EXISTS idx_jobs_job_url ON jobs(job_url);
+CREATE INDEX IF NOT EXISTS idx_jobs_company_id ON jobs(company_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_jobs_location ON jobs(location);
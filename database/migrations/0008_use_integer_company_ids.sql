@@ -0,0 +1,69 @@
+-- SQLite migration: Give companies a real INTEGER PRIMARY KEY and link jobs
+-- to them with a foreign key.
+-- The old TEXT PRIMARY KEY accepted NULL, so companies inserted without an
+-- explicit id had a NULL id while their jobs were linked to the rowid
+-- returned by LastInsertId. The rowid therefore becomes the new id.
+
+CREATE TABLE companies_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    site_url TEXT,
+    careers_url TEXT,
+    ats_type TEXT,
+    ats_url TEXT,
+    disabled INTEGER NOT NULL DEFAULT 0,
+    tags TEXT,
+    scraped_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+INSERT INTO companies_new (id, name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, created_at, updated_at)
+SELECT rowid, name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, created_at, updated_at
+FROM companies;
+
+CREATE TABLE jobs_new (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    company TEXT NOT NULL,
+    company_id INTEGER REFERENCES companies(id) ON DELETE CASCADE,
+    description TEXT,
+    job_url TEXT NOT NULL UNIQUE,
+    salary_range TEXT,
+    location TEXT,
+    published_at TEXT,
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Resolve each job's company by its old explicit id, then by rowid, then by name
+INSERT INTO jobs_new (id, title, company, company_id, description, job_url, salary_range, location, published_at, updated_at, created_at)
+SELECT
+    j.id,
+    j.title,
+    j.company,
+    COALESCE(
+        (SELECT c.rowid FROM companies c WHERE c.id IS NOT NULL AND CAST(c.id AS TEXT) = CAST(j.company_id AS TEXT)),
+        (SELECT c.rowid FROM companies c WHERE CAST(c.rowid AS TEXT) = CAST(j.company_id AS TEXT)),
+        (SELECT c.rowid FROM companies c WHERE c.name = j.company ORDER BY c.rowid LIMIT 1)
+    ),
+    j.description,
+    j.job_url,
+    j.salary_range,
+    j.location,
+    j.published_at,
+    j.updated_at,
+    j.created_at
+FROM jobs j;
+
+DROP TABLE jobs;
+DROP TABLE companies;
+ALTER TABLE companies_new RENAME TO companies;
+ALTER TABLE jobs_new RENAME TO jobs;
+
+CREATE INDEX IF NOT EXISTS idx_companies_ats_type ON companies(ats_type);
+CREATE INDEX IF NOT EXISTS idx_companies_name ON companies(name);
+CREATE INDEX IF NOT EXISTS idx_jobs_company ON jobs(company);
+CREATE INDEX IF NOT EXISTS idx_jobs_company_id ON jobs(company_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_jobs_location ON jobs(location);
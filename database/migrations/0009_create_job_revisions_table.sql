@@ -0,0 +1,16 @@
+-- SQLite migration: Create job_revisions table
+-- Records the previous values of a job's tracked fields whenever a scrape
+-- changes them, with a summary such as "salary added, title changed"
+
+CREATE TABLE IF NOT EXISTS job_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    summary TEXT NOT NULL,
+    title TEXT,
+    description TEXT,
+    salary_range TEXT,
+    location TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_revisions_job_id ON job_revisions(job_id, created_at DESC);
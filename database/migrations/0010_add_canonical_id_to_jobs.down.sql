@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_canonical_id;
+
+ALTER TABLE jobs DROP COLUMN canonical_id;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_jobs_company_external_id;
+
+ALTER TABLE jobs DROP COLUMN apply_url;
+ALTER TABLE jobs DROP COLUMN external_id;
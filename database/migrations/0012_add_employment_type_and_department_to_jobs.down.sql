@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_jobs_employment_type;
+
+ALTER TABLE jobs DROP COLUMN department;
+ALTER TABLE jobs DROP COLUMN employment_type;
@@ -0,0 +1,8 @@
+-- SQLite migration: Add employment_type and department to jobs
+-- employment_type is normalized to full-time, part-time, contract or
+-- internship, department is kept as the ATS reports it.
+
+ALTER TABLE jobs ADD COLUMN employment_type TEXT;
+ALTER TABLE jobs ADD COLUMN department TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_employment_type ON jobs(employment_type);
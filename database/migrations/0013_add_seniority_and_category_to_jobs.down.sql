@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_jobs_category;
+DROP INDEX IF EXISTS idx_jobs_seniority;
+
+ALTER TABLE jobs DROP COLUMN category;
+ALTER TABLE jobs DROP COLUMN seniority;
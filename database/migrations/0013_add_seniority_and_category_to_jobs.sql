@@ -0,0 +1,9 @@
+-- SQLite migration: Add seniority and category to jobs
+-- Both are tags given by the rules in internal/scraping/classification.yml,
+-- filled in when jobs are next scraped.
+
+ALTER TABLE jobs ADD COLUMN seniority TEXT;
+ALTER TABLE jobs ADD COLUMN category TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_seniority ON jobs(seniority);
+CREATE INDEX IF NOT EXISTS idx_jobs_category ON jobs(category);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_language;
+
+ALTER TABLE jobs DROP COLUMN language;
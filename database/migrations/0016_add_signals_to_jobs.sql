@@ -0,0 +1,5 @@
+-- SQLite migration: Add signals to jobs
+-- Comma-separated flags found in job descriptions, such as
+-- location-restricted or visa-sponsorship, filled in when jobs are scraped.
+
+ALTER TABLE jobs ADD COLUMN signals TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN tz_max_offset;
+ALTER TABLE jobs DROP COLUMN tz_min_offset;
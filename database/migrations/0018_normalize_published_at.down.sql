@@ -0,0 +1,2 @@
+UPDATE jobs SET published_at = published_at_raw WHERE published_at_raw IS NOT NULL;
+ALTER TABLE jobs DROP COLUMN published_at_raw;
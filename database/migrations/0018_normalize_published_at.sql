@@ -0,0 +1,16 @@
+-- SQLite migration: Normalize published_at
+-- published_at held whatever date string the ATS returned, which doesn't sort
+-- across ATSs. Keep it in published_at_raw and store it as UTC RFC3339.
+-- Lever jobs were dated with the scrape time, so they get no raw date and
+-- take the posting's creation date when next scraped.
+
+ALTER TABLE jobs ADD COLUMN published_at_raw TEXT;
+
+UPDATE jobs SET published_at_raw = published_at
+WHERE published_at <> ''
+  AND NOT EXISTS (SELECT 1 FROM companies c WHERE c.id = jobs.company_id AND c.ats_type = 'lever');
+
+-- strftime understands ISO dates with or without an offset, converting them to
+-- UTC, and returns NULL for anything else
+UPDATE jobs SET published_at = strftime('%Y-%m-%dT%H:%M:%SZ', REPLACE(published_at, ' UTC', ''))
+WHERE published_at IS NOT NULL;
@@ -0,0 +1,12 @@
+-- SQLite migration: Create board_states table
+-- The ETag, Last-Modified and body hash of each company's board as last
+-- fetched, so scrapes skip the boards which haven't changed.
+
+CREATE TABLE IF NOT EXISTS board_states (
+    company_id INTEGER PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    etag TEXT,
+    last_modified TEXT,
+    content_hash TEXT,
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
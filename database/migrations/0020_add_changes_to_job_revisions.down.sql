@@ -0,0 +1 @@
+ALTER TABLE job_revisions DROP COLUMN changes;
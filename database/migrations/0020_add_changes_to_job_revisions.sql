@@ -0,0 +1,8 @@
+-- SQLite migration: Add changes to job_revisions
+-- The fields a revision changed and how, e.g. "salary:added,title:changed",
+-- so pages don't have to parse the summary. Earlier revisions get them from
+-- their summary, e.g. "salary added, title changed".
+
+ALTER TABLE job_revisions ADD COLUMN changes TEXT;
+
+UPDATE job_revisions SET changes = REPLACE(REPLACE(summary, ', ', ','), ' ', ':');
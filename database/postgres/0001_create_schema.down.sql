@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS company_submissions;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS companies;
@@ -0,0 +1,58 @@
+-- PostgreSQL migration: Create the schema
+-- Matches the SQLite schema as of 0008_use_integer_company_ids, timestamps
+-- are TIMESTAMPTZ and job titles are indexed for full-text search
+
+CREATE TABLE companies (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL,
+    site_url TEXT,
+    careers_url TEXT,
+    ats_type TEXT,
+    ats_url TEXT,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    tags TEXT,
+    scraped_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_companies_ats_type ON companies(ats_type);
+CREATE INDEX idx_companies_name ON companies(name);
+
+CREATE TABLE jobs (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    company TEXT NOT NULL,
+    company_id BIGINT REFERENCES companies(id) ON DELETE CASCADE,
+    description TEXT,
+    job_url TEXT NOT NULL UNIQUE,
+    salary_range TEXT,
+    location TEXT,
+    published_at TEXT,
+    search tsvector GENERATED ALWAYS AS (to_tsvector('simple', title)) STORED,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_jobs_company ON jobs(company);
+CREATE INDEX idx_jobs_company_id ON jobs(company_id);
+CREATE INDEX idx_jobs_created_at ON jobs(created_at DESC);
+CREATE INDEX idx_jobs_location ON jobs(location);
+CREATE INDEX idx_jobs_search ON jobs USING GIN (search);
+
+CREATE TABLE company_submissions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT,
+    site_url TEXT,
+    careers_url TEXT NOT NULL,
+    ats_type TEXT,
+    ats_url TEXT,
+    submitter_email TEXT,
+    notes TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_company_submissions_status ON company_submissions(status);
@@ -0,0 +1 @@
+ALTER TABLE jobs DROP COLUMN canonical_id;
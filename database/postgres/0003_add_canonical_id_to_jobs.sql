@@ -0,0 +1,7 @@
+-- PostgreSQL migration: Add canonical_id to jobs
+-- Set by the dedup stage on jobs that duplicate another job posted under a
+-- different URL. Listings only show jobs whose canonical_id is NULL.
+
+ALTER TABLE jobs ADD COLUMN canonical_id TEXT REFERENCES jobs(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_jobs_canonical_id ON jobs(canonical_id);
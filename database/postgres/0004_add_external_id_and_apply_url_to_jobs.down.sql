@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN apply_url;
+ALTER TABLE jobs DROP COLUMN external_id;
@@ -0,0 +1,8 @@
+-- PostgreSQL migration: Add external_id and apply_url to jobs
+-- external_id is the job's ID in its ATS. Jobs are matched on it before
+-- job_url, so they keep their identity when an ATS changes its URL format.
+
+ALTER TABLE jobs ADD COLUMN external_id TEXT;
+ALTER TABLE jobs ADD COLUMN apply_url TEXT;
+
+CREATE UNIQUE INDEX idx_jobs_company_external_id ON jobs(company_id, external_id) WHERE external_id IS NOT NULL;
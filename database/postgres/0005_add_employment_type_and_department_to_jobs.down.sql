@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN department;
+ALTER TABLE jobs DROP COLUMN employment_type;
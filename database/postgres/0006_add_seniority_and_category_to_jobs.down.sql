@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN category;
+ALTER TABLE jobs DROP COLUMN seniority;
@@ -0,0 +1,11 @@
+-- PostgreSQL migration: Create job_tags table
+-- Links jobs to the skill tags of internal/scraping/skills.yml they mention,
+-- replaced whenever a job is scraped.
+
+CREATE TABLE job_tags (
+    job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (job_id, tag)
+);
+
+CREATE INDEX idx_job_tags_tag ON job_tags(tag);
@@ -0,0 +1,7 @@
+-- PostgreSQL migration: Add language to jobs
+-- The ISO 639-1 code of the language a job is posted in, detected from its
+-- title and description when it is scraped.
+
+ALTER TABLE jobs ADD COLUMN language TEXT;
+
+CREATE INDEX idx_jobs_language ON jobs(language);
@@ -0,0 +1,6 @@
+-- PostgreSQL migration: Add timezone window to jobs
+-- Range of UTC offsets, in minutes, a job expects candidates to work from,
+-- parsed from its description or location when jobs are scraped.
+
+ALTER TABLE jobs ADD COLUMN tz_min_offset INTEGER;
+ALTER TABLE jobs ADD COLUMN tz_max_offset INTEGER;
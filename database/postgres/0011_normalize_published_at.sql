@@ -0,0 +1,30 @@
+-- PostgreSQL migration: Normalize published_at
+-- published_at held whatever date string the ATS returned, which doesn't sort
+-- across ATSs. Keep it in published_at_raw and store it as UTC RFC3339.
+-- Lever jobs were dated with the scrape time, so they get no raw date and
+-- take the posting's creation date when next scraped.
+
+ALTER TABLE jobs ADD COLUMN published_at_raw TEXT;
+
+UPDATE jobs SET published_at_raw = published_at
+WHERE published_at <> ''
+  AND NOT EXISTS (SELECT 1 FROM companies c WHERE c.id = jobs.company_id AND c.ats_type = 'lever');
+
+-- Dates without an offset are UTC, as in the SQLite migration
+SET LOCAL TimeZone = 'UTC';
+
+-- Like strftime in the SQLite migration, this returns NULL for anything it
+-- can't parse, e.g. 2024-13-45, instead of failing the whole migration
+CREATE FUNCTION pg_temp.normalize_published_at(value TEXT) RETURNS TEXT AS $$
+BEGIN
+    IF value !~ '^\d{4}-\d{2}-\d{2}' THEN
+        RETURN NULL;
+    END IF;
+    RETURN to_char(REPLACE(value, ' UTC', '+00')::timestamptz AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"');
+EXCEPTION WHEN data_exception THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE jobs SET published_at = pg_temp.normalize_published_at(published_at)
+WHERE published_at IS NOT NULL;
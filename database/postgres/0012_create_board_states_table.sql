@@ -0,0 +1,12 @@
+-- PostgreSQL migration: Create board_states table
+-- The ETag, Last-Modified and body hash of each company's board as last
+-- fetched, so scrapes skip the boards which haven't changed.
+
+CREATE TABLE board_states (
+    company_id BIGINT PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    etag TEXT,
+    last_modified TEXT,
+    content_hash TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -19,24 +19,24 @@ fi
 if [ "$TARGET" = "migrate" ]; then
     echo "Deploying migrations to $SSH_CONNECTION..."
     
+    # Migrations are embedded in the wfe binary, no sqlite3 needed on the host
+    make wfe
+    
     # Ensure data directory exists with proper permissions
     ssh "$SSH_CONNECTION" "sudo mkdir -p /opt/workfromearth/data && sudo chown wfe:wfe /opt/workfromearth/data && sudo chmod 700 /opt/workfromearth/data"
     
-    echo "Copying migration files..."
-    scp -r database/migrations "$SSH_CONNECTION:/tmp/migrations_$$"
-    ssh "$SSH_CONNECTION" "sudo rm -rf /opt/workfromearth/data/migrations && sudo mv /tmp/migrations_$$ /opt/workfromearth/data/migrations && sudo chown -R wfe:wfe /opt/workfromearth/data/migrations"
-    
-    MIGRATE_SCRIPT="/opt/workfromearth/migrate.sh"
-    TEMP_SCRIPT="/tmp/migrate_$$.sh"
-    scp migrate.sh "$SSH_CONNECTION:$TEMP_SCRIPT"
-    ssh "$SSH_CONNECTION" "sudo mv $TEMP_SCRIPT $MIGRATE_SCRIPT && sudo chmod 755 $MIGRATE_SCRIPT && sudo chown wfe:wfe $MIGRATE_SCRIPT"
+    WFE_PATH="/opt/workfromearth/bin/wfe"
+    TEMP_PATH="/tmp/wfe_$$"
+    ssh "$SSH_CONNECTION" "sudo mkdir -p /opt/workfromearth/bin && sudo chown wfe:wfe /opt/workfromearth/bin"
+    scp bin/wfe "$SSH_CONNECTION:$TEMP_PATH"
+    ssh "$SSH_CONNECTION" "sudo mv $TEMP_PATH $WFE_PATH && sudo chmod 755 $WFE_PATH && sudo chown wfe:wfe $WFE_PATH"
     
     # Ensure database file has proper permissions (if it exists)
     DB_PATH="/opt/workfromearth/data/db.sqlite"
     ssh "$SSH_CONNECTION" "if [ -f $DB_PATH ]; then sudo chown wfe:wfe $DB_PATH && sudo chmod 644 $DB_PATH; fi"
     
     echo "Running migrations on remote server..."
-    ssh "$SSH_CONNECTION" "sudo -u wfe $MIGRATE_SCRIPT $DB_PATH"
+    ssh "$SSH_CONNECTION" "sudo -u wfe $WFE_PATH migrate -db $DB_PATH"
     
     echo "Migration deployment complete!"
 else
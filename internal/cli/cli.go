@@ -0,0 +1,165 @@
+// Package cli implements the wfe command line: company management,
+// scraping, serving the site and database maintenance.
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ddahon/workfromearth/internal/config"
+	"github.com/ddahon/workfromearth/internal/storage"
+	"github.com/ddahon/workfromearth/internal/storage/postgres"
+)
+
+// ErrUsage is returned when the command line is invalid. The usage has
+// already been printed to stderr by then.
+var ErrUsage = errors.New("invalid usage")
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"company", "Add, list, show, edit, disable, delete, import and export companies", runCompany},
+		{"scrape", "Scrape job boards and save the jobs", runScrape},
+		{"serve", "Serve the job board", runServe},
+		{"migrate", "Apply database migrations", runMigrate},
+		{"db", "Database maintenance (stats, backup, dedup)", runDB},
+	}
+}
+
+// Run executes the command named by args[0] with the remaining arguments
+func Run(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return ErrUsage
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			err := cmd.run(args[1:])
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage()
+	return ErrUsage
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: wfe <command> [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nEvery command accepts -config and -db. Settings are read from the config file,\n")
+	fmt.Fprintf(os.Stderr, "then WFE_* environment variables (e.g. WFE_DBPATH), then flags.\n")
+	fmt.Fprintf(os.Stderr, "Set dbDriver to postgres and dbURL (WFE_DBDRIVER, WFE_DBURL) to use PostgreSQL.\n")
+	fmt.Fprintf(os.Stderr, "Run 'wfe <command> -h' for the options of a command.\n")
+}
+
+// globalFlags are accepted by every command
+type globalFlags struct {
+	configPath string
+	dbPath     string
+}
+
+// newFlagSet creates the flag set of a command with the global flags registered
+func newFlagSet(name, synopsis string) (*flag.FlagSet, *globalFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	g := &globalFlags{}
+	fs.StringVar(&g.configPath, "config", os.Getenv("WFE_CONFIG"), "Path to the config file (env WFE_CONFIG)")
+	fs.StringVar(&g.dbPath, "db", "", "Path to the SQLite database file, overrides dbPath from the config")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: wfe %s\n\nOptions:\n", synopsis)
+		fs.PrintDefaults()
+	}
+	return fs, g
+}
+
+// parse parses args, printing usage and returning ErrUsage on invalid flags
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return ErrUsage
+	}
+	return nil
+}
+
+func (g *globalFlags) load() (config.Config, error) {
+	overrides := map[string]any{}
+	if g.dbPath != "" {
+		overrides["dbPath"] = g.dbPath
+	}
+	return config.Load(g.configPath, overrides)
+}
+
+// openRepository loads the configuration and opens the database it points to.
+// The returned function closes the database.
+func (g *globalFlags) openRepository() (storage.Store, func() error, error) {
+	cfg, err := g.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.Store(), db.Close, nil
+}
+
+// openDatabase opens the SQLite or PostgreSQL database selected by cfg.DBDriver
+func openDatabase(cfg config.Config) (storage.Database, error) {
+	if cfg.DBDriver == "postgres" {
+		return postgres.NewDB(cfg.DBURL)
+	}
+	return storage.NewDB(cfg.DBPath, cfg.SQLite)
+}
+
+// databaseName describes the database cfg points to without leaking the
+// PostgreSQL password
+func databaseName(cfg config.Config) string {
+	if cfg.DBDriver != "postgres" {
+		return cfg.DBPath
+	}
+	if u, err := url.Parse(cfg.DBURL); err == nil && u.Host != "" {
+		return u.Redacted()
+	}
+	return "postgres"
+}
+
+// usageError prints msg and the command usage, then returns ErrUsage
+func usageError(fs *flag.FlagSet, msg string) error {
+	fmt.Fprintf(os.Stderr, "%s\n\n", msg)
+	fs.Usage()
+	return ErrUsage
+}
+
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,520 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ddahon/workfromearth/internal/companyfile"
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+var companyCommands []command
+
+func init() {
+	companyCommands = []command{
+		{"add", "Create a company, detecting its ATS from the careers page if needed", runCompanyAdd},
+		{"list", "List companies", runCompanyList},
+		{"show", "Show a company by ID or URL", runCompanyShow},
+		{"edit", "Change fields of a company", runCompanyEdit},
+		{"disable", "Stop scraping a company", runCompanySetDisabled(true)},
+		{"enable", "Resume scraping a disabled company", runCompanySetDisabled(false)},
+		{"delete", "Delete a company and its jobs", runCompanyDelete},
+		{"import", "Create or update companies from a CSV, YAML or JSON file", runCompanyImport},
+		{"export", "Write all companies as CSV, YAML or JSON", runCompanyExport},
+	}
+}
+
+func runCompany(args []string) error {
+	if len(args) > 0 {
+		for _, cmd := range companyCommands {
+			if cmd.name == args[0] {
+				return cmd.run(args[1:])
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Usage: wfe company <command> [options]\n\nCommands:\n")
+	for _, cmd := range companyCommands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "help") {
+		return nil
+	}
+	return ErrUsage
+}
+
+func runCompanyAdd(args []string) error {
+	fs, global := newFlagSet("company add", "company add [options] [name] [siteurl] [atstype] [slug]")
+	nameFlag := fs.String("name", "", "Company name (required)")
+	siteURLFlag := fs.String("siteurl", "", "Company website URL (required)")
+	careersURLFlag := fs.String("careersurl", "", "Company careers page URL. Alone, the ATS, name and site URL are detected from it")
+	atsTypeFlag := fs.String("atstype", "", "ATS type ("+strings.Join(scraping.ATSTypes(), ", ")+")")
+	atsURLFlag := fs.String("atsurl", "", "ATS URL (required if atstype is provided)")
+	slugFlag := fs.String("slug", "", "Company slug for auto-filling careersUrl and atsUrl")
+	tagsFlag := fs.String("tags", "", "Comma-separated tags")
+	yesFlag := fs.Bool("yes", false, "Save without asking for confirmation after the preview scrape")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	// Positional arguments override flags
+	name, siteURL, atsType, slug := *nameFlag, *siteURLFlag, *atsTypeFlag, *slugFlag
+	positional := []*string{&name, &siteURL, &atsType, &slug}
+	for i, arg := range fs.Args() {
+		if i < len(positional) {
+			*positional[i] = arg
+		}
+	}
+
+	careersURL := *careersURLFlag
+	atsURL := *atsURLFlag
+
+	autoDetected := false
+	if atsType == "" && slug == "" && atsURL == "" && careersURL != "" {
+		detected, err := scraping.DetectATS(careersURL)
+		if err != nil {
+			return fmt.Errorf("detecting ATS from %s: %w (use -atstype and -slug instead)", careersURL, err)
+		}
+		fmt.Printf("Detected %s board %q\n", detected.Type, detected.Slug)
+
+		atsType = detected.Type
+		atsURL = detected.URL
+		autoDetected = true
+		if name == "" {
+			name = nameFromSlug(detected.Slug)
+		}
+		if siteURL == "" {
+			siteURL = siteURLFromCareersURL(careersURL)
+		}
+	}
+
+	if name == "" {
+		return usageError(fs, "name is required (use -name flag or provide as first positional argument)")
+	}
+	if siteURL == "" {
+		return usageError(fs, "siteurl is required (use -siteurl flag or provide as second positional argument)")
+	}
+
+	if slug != "" {
+		if atsType == "" {
+			return usageError(fs, "atstype is required when slug is provided")
+		}
+
+		ats, ok := scraping.LookupATS(atsType)
+		if !ok {
+			return fmt.Errorf("slug is not supported for atstype %q (supported: %s)", atsType, strings.Join(scraping.ATSTypes(), ", "))
+		}
+		if err := ats.ValidateSlug(slug); err != nil {
+			return err
+		}
+		if careersURL == "" {
+			careersURL = ats.CareersURL(slug)
+		}
+		if atsURL == "" {
+			atsURL = ats.APIURL(slug)
+		}
+	}
+
+	if atsType != "" && atsURL == "" {
+		return usageError(fs, "atsurl is required when atstype is provided (or use slug to auto-fill)")
+	}
+
+	company := scraping.Company{
+		Name:       name,
+		SiteURL:    siteURL,
+		CareersURL: careersURL,
+		ATSType:    atsType,
+		ATSUrl:     atsURL,
+		Tags:       scraping.SplitTags(*tagsFlag),
+	}
+
+	if autoDetected {
+		if err := previewScrape(company); err != nil {
+			return fmt.Errorf("preview scrape: %w", err)
+		}
+		if !*yesFlag && !confirm("Save this company?") {
+			fmt.Println("Aborted, nothing was saved")
+			return nil
+		}
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	id, err := repo.SaveCompany(company)
+	if err != nil {
+		return fmt.Errorf("saving company: %w", err)
+	}
+
+	fmt.Printf("Successfully created company: %s (ID: %d)\n", company.Name, id)
+	return nil
+}
+
+func runCompanyList(args []string) error {
+	fs, global := newFlagSet("company list", "company list [options]")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	companies, err := repo.GetCompanies()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tATS\tSTATUS\tSCRAPED AT")
+	for _, c := range companies {
+		status := "enabled"
+		if c.Disabled {
+			status = "disabled"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", c.ID, c.Name, c.ATSType, status, c.ScrapedAt)
+	}
+	return w.Flush()
+}
+
+func runCompanyShow(args []string) error {
+	fs, global := newFlagSet("company show", "company show [options] <id|url>")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(fs, "expected a company ID or URL")
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	c, err := lookupCompany(repo, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%d\n", c.ID)
+	fmt.Fprintf(w, "Name:\t%s\n", c.Name)
+	fmt.Fprintf(w, "Site URL:\t%s\n", c.SiteURL)
+	fmt.Fprintf(w, "Careers URL:\t%s\n", c.CareersURL)
+	fmt.Fprintf(w, "ATS type:\t%s\n", c.ATSType)
+	fmt.Fprintf(w, "ATS URL:\t%s\n", c.ATSUrl)
+	fmt.Fprintf(w, "Tags:\t%s\n", strings.Join(c.Tags, ", "))
+	fmt.Fprintf(w, "Disabled:\t%t\n", c.Disabled)
+	fmt.Fprintf(w, "Scraped at:\t%s\n", c.ScrapedAt)
+	fmt.Fprintf(w, "Created at:\t%s\n", c.CreatedAt)
+	fmt.Fprintf(w, "Updated at:\t%s\n", c.UpdatedAt)
+	return w.Flush()
+}
+
+func runCompanyEdit(args []string) error {
+	fs, global := newFlagSet("company edit", "company edit [options] <id|url>")
+	name := fs.String("name", "", "New company name")
+	siteURL := fs.String("siteurl", "", "New website URL")
+	careersURL := fs.String("careersurl", "", "New careers page URL")
+	atsType := fs.String("atstype", "", "New ATS type ("+strings.Join(scraping.ATSTypes(), ", ")+")")
+	atsURL := fs.String("atsurl", "", "New ATS URL")
+	slug := fs.String("slug", "", "New slug, fills atsurl from the ATS type")
+	tags := fs.String("tags", "", "New comma-separated tags (empty to clear)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(fs, "expected a company ID or URL")
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	company, err := lookupCompany(repo, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	// Only the flags given on the command line are applied
+	changed := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			company.Name = *name
+		case "siteurl":
+			company.SiteURL = *siteURL
+		case "careersurl":
+			company.CareersURL = *careersURL
+		case "atstype":
+			company.ATSType = *atsType
+		case "atsurl":
+			company.ATSUrl = *atsURL
+		case "tags":
+			company.Tags = scraping.SplitTags(*tags)
+		default:
+			return
+		}
+		changed = true
+	})
+
+	if *slug != "" {
+		ats, ok := scraping.LookupATS(company.ATSType)
+		if !ok {
+			return fmt.Errorf("slug is not supported for atstype %q", company.ATSType)
+		}
+		if err := ats.ValidateSlug(*slug); err != nil {
+			return err
+		}
+		company.ATSUrl = ats.APIURL(*slug)
+		changed = true
+	}
+
+	if !changed {
+		return usageError(fs, "nothing to change")
+	}
+	if err := scraping.ValidateCompany(*company); err != nil {
+		return err
+	}
+
+	if _, err := repo.SaveCompany(*company); err != nil {
+		return fmt.Errorf("saving company: %w", err)
+	}
+	fmt.Printf("Updated company: %s (ID: %d)\n", company.Name, company.ID)
+	return nil
+}
+
+func runCompanySetDisabled(disabled bool) func(args []string) error {
+	name := "enable"
+	if disabled {
+		name = "disable"
+	}
+
+	return func(args []string) error {
+		fs, global := newFlagSet("company "+name, "company "+name+" [options] <id|url>")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return usageError(fs, "expected a company ID or URL")
+		}
+
+		repo, closeDB, err := global.openRepository()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		company, err := lookupCompany(repo, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if err := repo.SetCompanyDisabled(company.ID, disabled); err != nil {
+			return err
+		}
+		fmt.Printf("%sd company: %s (ID: %d)\n", strings.ToUpper(name[:1])+name[1:], company.Name, company.ID)
+		return nil
+	}
+}
+
+func runCompanyDelete(args []string) error {
+	fs, global := newFlagSet("company delete", "company delete [options] <id|url>")
+	yes := fs.Bool("yes", false, "Delete without asking for confirmation")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(fs, "expected a company ID or URL")
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	company, err := lookupCompany(repo, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if !*yes && !confirm(fmt.Sprintf("Delete %s (ID: %d) and all its jobs?", company.Name, company.ID)) {
+		fmt.Println("Aborted, nothing was deleted")
+		return nil
+	}
+	if err := repo.DeleteCompany(company.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted company: %s (ID: %d)\n", company.Name, company.ID)
+	return nil
+}
+
+func runCompanyImport(args []string) error {
+	fs, global := newFlagSet("company import", "company import [options] <file>")
+	formatFlag := fs.String("format", "", "File format: csv, yaml or json (default: guessed from the file extension)")
+	dryRun := fs.Bool("dry-run", false, "Report what would change without writing to the database")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(fs, "import expects exactly one file")
+	}
+	path := fs.Arg(0)
+
+	format := *formatFlag
+	if format == "" {
+		var err error
+		if format, err = companyfile.FormatFromPath(path); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	records, err := companyfile.Read(f, format)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	result, err := companyfile.Import(repo, records, *dryRun)
+	for _, row := range result.Rows {
+		fmt.Printf("%-9s %s (%s)\n", row.Action, row.Name, row.CareersURL)
+	}
+	if err != nil {
+		return fmt.Errorf("importing companies: %w", err)
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "Dry run: "
+	}
+	fmt.Printf("\n%s%d created, %d updated, %d unchanged\n", prefix, result.Created, result.Updated, result.Unchanged)
+	return nil
+}
+
+func runCompanyExport(args []string) error {
+	fs, global := newFlagSet("company export", "company export [options] [file]")
+	formatFlag := fs.String("format", "", "File format: csv, yaml or json (default: guessed from the file extension, csv on stdout)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return usageError(fs, "export expects at most one file")
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	records, err := companyfile.Export(repo)
+	if err != nil {
+		return fmt.Errorf("exporting companies: %w", err)
+	}
+
+	format := *formatFlag
+	out := os.Stdout
+	if path := fs.Arg(0); path != "" && path != "-" {
+		if format == "" {
+			if format, err = companyfile.FormatFromPath(path); err != nil {
+				return err
+			}
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if format == "" {
+		format = companyfile.FormatCSV
+	}
+
+	return companyfile.Write(out, format, records)
+}
+
+// lookupCompany finds a company by numeric ID, or else by careers or ATS URL
+func lookupCompany(repo storage.CompanyStore, idOrURL string) (*scraping.Company, error) {
+	if id, err := strconv.ParseInt(idOrURL, 10, 64); err == nil {
+		return repo.GetCompanyByID(id)
+	}
+	company, err := repo.GetCompanyByURL(idOrURL)
+	if errors.Is(err, storage.ErrCompanyNotFound) {
+		return nil, fmt.Errorf("%w matching %q", storage.ErrCompanyNotFound, idOrURL)
+	}
+	return company, err
+}
+
+// previewScrape scrapes the company without saving anything and prints a
+// summary so the detected settings can be checked
+func previewScrape(company scraping.Company) error {
+	jobs, err := scraping.PreviewScrape(company)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n", company.Name)
+	fmt.Printf("  site:    %s\n", company.SiteURL)
+	fmt.Printf("  careers: %s\n", company.CareersURL)
+	fmt.Printf("  ats:     %s (%s)\n", company.ATSType, company.ATSUrl)
+	fmt.Printf("  %d remote jobs found\n", len(jobs))
+	for i, job := range jobs {
+		if i == 5 {
+			fmt.Printf("    ...\n")
+			break
+		}
+		fmt.Printf("    - %s\n", job.Title)
+	}
+	fmt.Println()
+	return nil
+}
+
+// nameFromSlug turns an ATS slug such as "acme-labs" into "Acme Labs"
+func nameFromSlug(slug string) string {
+	words := strings.FieldsFunc(slug, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// siteURLFromCareersURL returns the origin of a careers page hosted on the
+// company's own domain, or an empty string for pages hosted by an ATS
+func siteURLFromCareersURL(careersURL string) string {
+	if _, ok := scraping.DetectATSFromURL(careersURL); ok {
+		return ""
+	}
+	u, err := url.Parse(careersURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
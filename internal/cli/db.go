@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ddahon/workfromearth/internal/dedup"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+func runDB(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "stats":
+			return runDBStats(args[1:])
+		case "backup":
+			return runDBBackup(args[1:])
+		case "dedup":
+			return runDBDedup(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Usage: wfe db <stats|backup|dedup> [options]\n")
+	return ErrUsage
+}
+
+func runDBStats(args []string) error {
+	fs, global := newFlagSet("db stats", "db stats [options]")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	stats, err := repo.Stats()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Companies:\t%d (%d disabled)\n", stats.Companies, stats.DisabledCompanies)
+	fmt.Fprintf(w, "Jobs:\t%d (%d without company)\n", stats.Jobs, stats.JobsWithoutCompany)
+	fmt.Fprintf(w, "Pending submissions:\t%d\n", stats.PendingSubmissions)
+	fmt.Fprintf(w, "Last scrape:\t%s\n", stats.LastScrapedAt)
+
+	atsTypes := make([]string, 0, len(stats.JobsByATS))
+	for atsType := range stats.JobsByATS {
+		atsTypes = append(atsTypes, atsType)
+	}
+	sort.Strings(atsTypes)
+	for _, atsType := range atsTypes {
+		fmt.Fprintf(w, "  %s jobs:\t%d\n", atsType, stats.JobsByATS[atsType])
+	}
+	return w.Flush()
+}
+
+func runDBBackup(args []string) error {
+	fs, global := newFlagSet("db backup", "db backup [options]")
+	dir := fs.String("dir", "", "Directory of the snapshots, overrides backup.dir from the config")
+	keep := fs.Int("keep", -1, "Number of snapshots to keep, 0 keeps all, overrides backup.keep from the config")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := global.load()
+	if err != nil {
+		return err
+	}
+	if *dir != "" {
+		cfg.Backup.Dir = *dir
+	}
+	if *keep >= 0 {
+		cfg.Backup.Keep = *keep
+	}
+	if cfg.DBDriver != "sqlite" {
+		return fmt.Errorf("db backup only supports SQLite, use pg_dump for PostgreSQL")
+	}
+
+	db, err := storage.NewDB(cfg.DBPath, cfg.SQLite)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	path, err := db.Backup(cfg.Backup.Dir, storage.BackupPrefix(cfg.DBPath), cfg.Backup.Keep)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Backed up %s to %s\n", cfg.DBPath, path)
+	return nil
+}
+
+func runDBDedup(args []string) error {
+	fs, global := newFlagSet("db dedup", "db dedup [options]")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	repo, closeDB, err := global.openRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	duplicates, err := dedup.Run(repo)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Marked %d jobs as duplicates\n", duplicates)
+	return nil
+}
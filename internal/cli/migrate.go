@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+func runMigrate(args []string) error {
+	fs, global := newFlagSet("migrate", "migrate [options]")
+	status := fs.Bool("status", false, "List migrations and whether they are applied, without changing anything")
+	down := fs.Int("down", 0, "Revert the given number of most recent migrations instead of applying pending ones")
+	baseline := fs.String("baseline", "", "Record migrations up to this version as applied without running them, for databases migrated by the old migrate.sh")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := global.load()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrations, err := db.Migrations()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *status:
+		return printMigrationStatus(db, migrations)
+
+	case *baseline != "":
+		if err := db.Baseline(migrations, *baseline); err != nil {
+			return err
+		}
+		fmt.Printf("Recorded migrations up to %s as applied\n", *baseline)
+		return nil
+
+	case *down > 0:
+		reverted, err := db.MigrateDown(migrations, *down)
+		for _, m := range reverted {
+			fmt.Printf("✓ Reverted %s_%s\n", m.Version, m.Name)
+		}
+		return err
+	}
+
+	fmt.Printf("Running migrations on database: %s\n", databaseName(cfg))
+	applied, err := db.Migrate(migrations)
+	for _, m := range applied {
+		fmt.Printf("✓ Applied %s_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("Database is up to date")
+	}
+	return nil
+}
+
+func printMigrationStatus(db storage.Migrator, migrations []storage.Migration) error {
+	applied, err := db.AppliedMigrations()
+	if err != nil {
+		return err
+	}
+	appliedAt := make(map[string]storage.AppliedMigration, len(applied))
+	for _, m := range applied {
+		appliedAt[m.Version] = m
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, m := range migrations {
+		status := "pending"
+		if a, ok := appliedAt[m.Version]; ok {
+			status = a.AppliedAt
+			if a.Checksum != m.Checksum {
+				status += " (modified since)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", m.Version, m.Name, status)
+	}
+	return w.Flush()
+}
+
+// autoMigrate applies pending migrations when a long-running command starts
+func autoMigrate(db storage.Migrator) error {
+	migrations, err := db.Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := db.Migrate(migrations)
+	for _, m := range applied {
+		log.Printf("applied migration %s_%s", m.Version, m.Name)
+	}
+	return err
+}
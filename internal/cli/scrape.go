@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ddahon/workfromearth/internal/dedup"
+	"github.com/ddahon/workfromearth/internal/scraping"
+)
+
+func runScrape(args []string) error {
+	fs, global := newFlagSet("scrape", "scrape [options]")
+	urlFlag := fs.String("url", "", "URL to scrape (searches in database by careers_url or ats_url)")
+	lastScrapedFlag := fs.Int("last_scraped", 6, "Minimum number of hours since last scrape to rescrape a company (0 = always scrape)")
+	forceFlag := fs.Bool("force", false, "Scrape every company, ignoring -last_scraped, and parse and save boards even when unchanged since their last scrape, e.g. to retag jobs")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := global.load()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if cfg.AutoMigrate {
+		if err := autoMigrate(db); err != nil {
+			return err
+		}
+	}
+	repo := db.Store()
+
+	// If URL is provided, scrape only that URL and print results
+	if *urlFlag != "" {
+		company, err := repo.GetCompanyByURL(*urlFlag)
+		if err != nil {
+			return fmt.Errorf("getting company: %w", err)
+		}
+
+		scraper, err := scraping.CompanyToScraper(*company)
+		if err != nil {
+			return fmt.Errorf("creating scraper: %w", err)
+		}
+
+		jobs, err := scraper.Scrape()
+		if err != nil {
+			return fmt.Errorf("scraping: %w", err)
+		}
+		scraping.Enrich(jobs)
+
+		// Print results as JSON
+		output := map[string]interface{}{
+			"company":    company.Name,
+			"url":        *urlFlag,
+			"ats_type":   company.ATSType,
+			"jobs_count": len(jobs),
+			"jobs":       jobs,
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("encoding output: %w", err)
+		}
+
+		return nil
+	}
+
+	// Default behavior: scrape all companies
+	companies, err := repo.GetCompanies()
+	if err != nil {
+		return fmt.Errorf("getting companies: %w", err)
+	}
+
+	var companiesToScrape []scraping.Company
+	now := time.Now()
+	minHoursSinceScrape := time.Duration(*lastScrapedFlag) * time.Hour
+
+	for _, company := range companies {
+		if company.Disabled {
+			continue
+		}
+
+		shouldScrape := false
+
+		if *forceFlag || company.ScrapedAt == "" {
+			shouldScrape = true
+		} else {
+			// Parse scraped_at timestamp
+			formats := []string{
+				"2006-01-02 15:04:05.000",
+				"2006-01-02 15:04:05",
+				time.RFC3339,
+			}
+			var scrapedAt time.Time
+			parsed := false
+			for _, format := range formats {
+				if t, err := time.Parse(format, company.ScrapedAt); err == nil {
+					scrapedAt = t
+					parsed = true
+					break
+				}
+			}
+
+			if !parsed {
+				shouldScrape = true
+			} else {
+				hoursSinceScrape := now.Sub(scrapedAt)
+				if hoursSinceScrape >= minHoursSinceScrape {
+					shouldScrape = true
+				}
+			}
+		}
+
+		if shouldScrape {
+			companiesToScrape = append(companiesToScrape, company)
+		}
+	}
+
+	log.Printf("Found %d companies, %d need scraping\n", len(companies), len(companiesToScrape))
+
+	for _, company := range companiesToScrape {
+		if company.ATSType == "custom" {
+			log.Printf("skipping %s: did not find any scraper for custom ATS", company.Name)
+			continue
+		}
+
+		scraper, err := scraping.CompanyToScraper(company)
+		if err != nil {
+			log.Printf("creating scraper for %s: %v\n", company.Name, err)
+			continue
+		}
+
+		var state scraping.BoardState
+		if !*forceFlag {
+			state, err = repo.GetBoardState(company.ID)
+			if err != nil {
+				log.Printf("getting board state for %s: %v\n", company.Name, err)
+			}
+		}
+
+		jobs, state, err := scraping.ScrapeIfChanged(scraper, state)
+		switch {
+		case errors.Is(err, scraping.ErrBoardUnchanged):
+			log.Printf("skipping %s: board unchanged since last scrape\n", company.Name)
+		case err != nil:
+			log.Printf("scraping %s: %v\n", company.Name, err)
+			continue
+		default:
+			scraping.Enrich(jobs)
+			if err := repo.SaveJobs(jobs, company.ID); err != nil {
+				log.Printf("saving %v jobs for %s: %v\n", len(jobs), company.Name, err)
+				continue
+			}
+		}
+
+		if state != (scraping.BoardState{}) {
+			if err := repo.SaveBoardState(company.ID, state); err != nil {
+				log.Printf("saving board state for %s: %v\n", company.Name, err)
+			}
+		}
+		if err := repo.UpdateScrapedAt(company.ID); err != nil {
+			log.Printf("updating scraped_at for %s: %v\n", company.Name, err)
+		}
+	}
+
+	duplicates, err := dedup.Run(repo)
+	if err != nil {
+		return fmt.Errorf("deduplicating jobs: %w", err)
+	}
+	log.Printf("Marked %d jobs as duplicates\n", duplicates)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ddahon/workfromearth/internal/web"
+)
+
+func runServe(args []string) error {
+	fs, global := newFlagSet("serve", "serve [options]")
+	port := fs.String("port", "", "Port to listen on, overrides port from the config")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := global.load()
+	if err != nil {
+		return err
+	}
+	if *port != "" {
+		cfg.Port = *port
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if cfg.AutoMigrate {
+		if err := autoMigrate(db); err != nil {
+			return err
+		}
+	}
+
+	handler := web.NewHandler(db.Store(), cfg.Admin)
+
+	log.Printf("Listening on :%s", cfg.Port)
+	return http.ListenAndServe(":"+cfg.Port, handler)
+}
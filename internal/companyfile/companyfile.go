@@ -0,0 +1,247 @@
+// Package companyfile reads and writes the curated companies list as CSV,
+// YAML or JSON so it can be maintained outside the database.
+package companyfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Record is one company as it appears in an import or export file. Either
+// Slug or ATSUrl identifies the ATS board; Slug is expanded using the ATS
+// registry. Enabled defaults to true when omitted.
+type Record struct {
+	Name       string   `json:"name" yaml:"name"`
+	SiteURL    string   `json:"site_url,omitempty" yaml:"site_url,omitempty"`
+	CareersURL string   `json:"careers_url" yaml:"careers_url"`
+	ATSType    string   `json:"ats_type,omitempty" yaml:"ats_type,omitempty"`
+	Slug       string   `json:"slug,omitempty" yaml:"slug,omitempty"`
+	ATSUrl     string   `json:"ats_url,omitempty" yaml:"ats_url,omitempty"`
+	Tags       []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Enabled    *bool    `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+var csvHeader = []string{"name", "site_url", "careers_url", "ats_type", "slug", "ats_url", "tags", "enabled"}
+
+// FormatFromPath guesses the file format from its extension
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("cannot guess format of %q, use one of csv, yaml, json", path)
+}
+
+func Read(r io.Reader, format string) ([]Record, error) {
+	var records []Record
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&records); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("decoding YAML: %w", err)
+		}
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, fmt.Errorf("decoding JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+	return records, nil
+}
+
+func Write(w io.Writer, format string, records []Record) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, records)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return fmt.Errorf("encoding YAML: %w", err)
+		}
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(records); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format: %s", format)
+}
+
+// readCSV maps columns by the header row, so columns may be reordered or omitted
+func readCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvHeader, name) {
+			return nil, fmt.Errorf("unknown CSV column %q, expected some of %s", name, strings.Join(csvHeader, ","))
+		}
+		columns[name] = i
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		record := Record{
+			Name:       get("name"),
+			SiteURL:    get("site_url"),
+			CareersURL: get("careers_url"),
+			ATSType:    get("ats_type"),
+			Slug:       get("slug"),
+			ATSUrl:     get("ats_url"),
+			Tags:       scraping.SplitTags(get("tags")),
+		}
+		if enabled := get("enabled"); enabled != "" {
+			value, err := parseBool(enabled)
+			if err != nil {
+				line, _ := reader.FieldPos(0)
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			record.Enabled = &value
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func writeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	for _, record := range records {
+		enabled := ""
+		if record.Enabled != nil {
+			enabled = strconv.FormatBool(*record.Enabled)
+		}
+		row := []string{
+			record.Name,
+			record.SiteURL,
+			record.CareersURL,
+			record.ATSType,
+			record.Slug,
+			record.ATSUrl,
+			strings.Join(record.Tags, ", "),
+			enabled,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("writing CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "1":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid enabled value %q", s)
+}
+
+// ToCompany validates the record and converts it to a company, expanding the
+// slug into the ATS API URL
+func (rec Record) ToCompany() (scraping.Company, error) {
+	if rec.Name == "" {
+		return scraping.Company{}, errors.New("name is required")
+	}
+	if rec.CareersURL == "" {
+		return scraping.Company{}, errors.New("careers_url is required")
+	}
+
+	atsURL := rec.ATSUrl
+	if rec.Slug != "" && atsURL == "" {
+		ats, ok := scraping.LookupATS(rec.ATSType)
+		if !ok {
+			return scraping.Company{}, fmt.Errorf("slug is not supported for ats_type %q", rec.ATSType)
+		}
+		if err := ats.ValidateSlug(rec.Slug); err != nil {
+			return scraping.Company{}, err
+		}
+		atsURL = ats.APIURL(rec.Slug)
+	}
+
+	company := scraping.Company{
+		Name:       rec.Name,
+		SiteURL:    rec.SiteURL,
+		CareersURL: rec.CareersURL,
+		ATSType:    rec.ATSType,
+		ATSUrl:     atsURL,
+		Tags:       rec.Tags,
+		Disabled:   rec.Enabled != nil && !*rec.Enabled,
+	}
+	if _, err := scraping.CompanyToScraper(company); err != nil {
+		return scraping.Company{}, err
+	}
+	if company.ATSType != "" && company.ATSUrl == "" {
+		return scraping.Company{}, errors.New("slug or ats_url is required when ats_type is set")
+	}
+	return company, nil
+}
+
+// FromCompany converts a company to a record for export
+func FromCompany(c scraping.Company) Record {
+	enabled := !c.Disabled
+	return Record{
+		Name:       c.Name,
+		SiteURL:    c.SiteURL,
+		CareersURL: c.CareersURL,
+		ATSType:    c.ATSType,
+		ATSUrl:     c.ATSUrl,
+		Tags:       c.Tags,
+		Enabled:    &enabled,
+	}
+}
@@ -0,0 +1,118 @@
+package companyfile
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionUnchanged = "unchanged"
+)
+
+// ImportedRow reports what happened to one record of an import
+type ImportedRow struct {
+	Name       string
+	CareersURL string
+	Action     string
+}
+
+type ImportResult struct {
+	Rows      []ImportedRow
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// Import upserts records into the companies table, matching existing
+// companies by careers URL. Every record is validated before anything is
+// written, and the changes are saved in one transaction, all or none of them.
+// With dryRun, the result reports what would change but the database is left
+// untouched.
+func Import(repo storage.CompanyStore, records []Record, dryRun bool) (ImportResult, error) {
+	companies := make([]scraping.Company, 0, len(records))
+	seen := make(map[string]int, len(records))
+	for i, record := range records {
+		company, err := record.ToCompany()
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("record %d (%s): %w", i+1, record.Name, err)
+		}
+		if previous, ok := seen[company.CareersURL]; ok {
+			return ImportResult{}, fmt.Errorf("record %d (%s): careers_url %s already used by record %d", i+1, record.Name, company.CareersURL, previous)
+		}
+		seen[company.CareersURL] = i + 1
+		companies = append(companies, company)
+	}
+
+	var result ImportResult
+	var changed []scraping.Company
+	for _, company := range companies {
+		action := ActionCreated
+
+		existing, err := repo.GetCompanyByCareersURL(company.CareersURL)
+		switch {
+		case err == nil:
+			if sameCompany(*existing, company) {
+				action = ActionUnchanged
+			} else {
+				action = ActionUpdated
+			}
+			company.ID = existing.ID
+			company.ScrapedAt = existing.ScrapedAt
+		case !errors.Is(err, storage.ErrCompanyNotFound):
+			return ImportResult{}, fmt.Errorf("looking up %s: %w", company.Name, err)
+		}
+
+		if action != ActionUnchanged {
+			changed = append(changed, company)
+		}
+
+		result.Rows = append(result.Rows, ImportedRow{Name: company.Name, CareersURL: company.CareersURL, Action: action})
+		switch action {
+		case ActionCreated:
+			result.Created++
+		case ActionUpdated:
+			result.Updated++
+		case ActionUnchanged:
+			result.Unchanged++
+		}
+	}
+
+	if !dryRun && len(changed) > 0 {
+		if _, err := repo.SaveCompanies(changed); err != nil {
+			return ImportResult{}, fmt.Errorf("saving companies, none was saved: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// sameCompany compares the fields managed through import files
+func sameCompany(a, b scraping.Company) bool {
+	return a.Name == b.Name &&
+		a.SiteURL == b.SiteURL &&
+		a.CareersURL == b.CareersURL &&
+		a.ATSType == b.ATSType &&
+		a.ATSUrl == b.ATSUrl &&
+		a.Disabled == b.Disabled &&
+		slices.Equal(a.Tags, b.Tags)
+}
+
+// Export returns every company as a record
+func Export(repo storage.CompanyStore) ([]Record, error) {
+	companies, err := repo.GetCompanies()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(companies))
+	for _, company := range companies {
+		records = append(records, FromCompany(company))
+	}
+	return records, nil
+}
@@ -0,0 +1,107 @@
+// Package config loads the settings shared by every command from an
+// optional YAML file, WFE_* environment variables and command-line flags,
+// in increasing order of precedence.
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	// DBDriver selects the storage backend, "sqlite" or "postgres"
+	DBDriver string `mapstructure:"dbDriver"`
+	// DBPath is the SQLite database file
+	DBPath string `mapstructure:"dbPath"`
+	// DBURL is the PostgreSQL connection URL
+	DBURL  string       `mapstructure:"dbURL"`
+	SQLite SQLiteConfig `mapstructure:"sqlite"`
+	Backup BackupConfig `mapstructure:"backup"`
+	// AutoMigrate applies pending migrations when serve or scrape starts
+	AutoMigrate bool        `mapstructure:"autoMigrate"`
+	Port        string      `mapstructure:"port"`
+	Admin       AdminConfig `mapstructure:"admin"`
+}
+
+// SQLiteConfig tunes the SQLite connections, shared between the server
+// reading and the scraper writing the same file
+type SQLiteConfig struct {
+	// JournalMode is wal by default so readers do not block the writer
+	JournalMode string        `mapstructure:"journalMode"`
+	BusyTimeout time.Duration `mapstructure:"busyTimeout"`
+	Synchronous string        `mapstructure:"synchronous"`
+	// MaxOpenConns limits the write pool, SQLite only allows one writer at a time
+	MaxOpenConns int `mapstructure:"maxOpenConns"`
+	// MaxReadConns limits the separate pool of query-only connections
+	MaxReadConns int `mapstructure:"maxReadConns"`
+}
+
+// BackupConfig configures `wfe db backup`
+type BackupConfig struct {
+	Dir string `mapstructure:"dir"`
+	// Keep is the number of snapshots kept, older ones are deleted
+	Keep int `mapstructure:"keep"`
+}
+
+type AdminConfig struct {
+	Username string `mapstructure:"username"`
+	// PasswordHash is a bcrypt hash; the admin area is disabled when empty
+	PasswordHash string `mapstructure:"passwordHash"`
+}
+
+var defaults = map[string]any{
+	"dbDriver":            "sqlite",
+	"dbPath":              "./db.sqlite",
+	"dbURL":               "",
+	"sqlite.journalMode":  "wal",
+	"sqlite.busyTimeout":  "5s",
+	"sqlite.synchronous":  "normal",
+	"sqlite.maxOpenConns": 1,
+	"sqlite.maxReadConns": 4,
+	"backup.dir":          "./backups",
+	"backup.keep":         7,
+	"autoMigrate":         true,
+	"port":                "8080",
+	"admin.username":      "admin",
+	"admin.passwordHash":  "",
+}
+
+// Load reads the config file at path, if any, then applies environment
+// variables (dbPath -> WFE_DBPATH, admin.passwordHash -> WFE_ADMIN_PASSWORDHASH)
+// and finally overrides, keyed like the config file.
+func Load(path string, overrides map[string]any) (Config, error) {
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	v.SetEnvPrefix("WFE")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return Config{}, fmt.Errorf("reading config file %v: %w", path, err)
+		}
+	}
+
+	for key, value := range overrides {
+		v.Set(key, value)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return Config{}, fmt.Errorf("decoding config: %w", err)
+	}
+	if cfg.DBDriver != "sqlite" && cfg.DBDriver != "postgres" {
+		return Config{}, fmt.Errorf("unknown dbDriver %q, expected sqlite or postgres", cfg.DBDriver)
+	}
+	if cfg.DBDriver == "postgres" && cfg.DBURL == "" {
+		return Config{}, fmt.Errorf("dbURL is required with the postgres dbDriver")
+	}
+	return cfg, nil
+}
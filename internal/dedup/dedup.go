@@ -0,0 +1,218 @@
+// Package dedup finds jobs posted more than once under different URLs, e.g.
+// on both an ATS board and a company's own board, or on the Greenhouse
+// boards of several subsidiaries, so listings only show one of them.
+package dedup
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+const (
+	// shingleSize is the number of consecutive words hashed together
+	shingleSize = 3
+	// sameCompanyDistance is the maximum number of differing simhash bits
+	// between descriptions of duplicates posted by the same company
+	sameCompanyDistance = 12
+	// crossCompanyDistance is stricter, the same title and location posted
+	// by different companies is only a duplicate if the text is near identical
+	crossCompanyDistance = 6
+)
+
+// legalSuffixes are ignored when comparing company names
+var legalSuffixes = map[string]bool{
+	"inc": true, "llc": true, "ltd": true, "limited": true, "gmbh": true, "ag": true,
+	"sa": true, "sas": true, "sarl": true, "bv": true, "plc": true, "corp": true, "co": true,
+}
+
+// Fingerprint summarizes a job for comparison
+type Fingerprint struct {
+	JobID   string
+	Company string
+	// Key is the normalized title and location, only jobs with the same
+	// key are compared
+	Key string
+	// SimHash is a locality-sensitive hash of the description shingles,
+	// similar descriptions differ in few bits
+	SimHash uint64
+	// HasText is false when the description has no words, so its SimHash
+	// says nothing about the content
+	HasText bool
+}
+
+// NewFingerprint computes the fingerprint of a job
+func NewFingerprint(job scraping.Job) Fingerprint {
+	company := ""
+	if job.Company != nil {
+		company = normalizeCompany(job.Company.Name)
+	}
+
+	words := Words(scraping.DescriptionText(job.Description))
+	return Fingerprint{
+		JobID:   job.ID,
+		Company: company,
+		Key:     strings.Join(Words(job.Title), " ") + "|" + strings.Join(Words(job.Location), " "),
+		SimHash: SimHash(Shingles(words, shingleSize)),
+		HasText: len(words) > 0,
+	}
+}
+
+// Duplicates reports whether a and b are the same job
+func Duplicates(a, b Fingerprint) bool {
+	if a.Key != b.Key {
+		return false
+	}
+	if a.Company != "" && a.Company == b.Company {
+		// Reposts by the same company often tweak the text, or have none
+		return !a.HasText || !b.HasText || Distance(a.SimHash, b.SimHash) <= sameCompanyDistance
+	}
+	return a.HasText && b.HasText && Distance(a.SimHash, b.SimHash) <= crossCompanyDistance
+}
+
+// Group returns, for every job that duplicates another one, the ID of the
+// canonical job of its group. The canonical job is the first one seen, by
+// CreatedAt then ID, so it stays the same as duplicates come and go.
+func Group(jobs []scraping.Job) map[string]string {
+	jobs = append([]scraping.Job(nil), jobs...)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	byKey := map[string][]Fingerprint{}
+	var keys []string
+	for _, job := range jobs {
+		fp := NewFingerprint(job)
+		if _, ok := byKey[fp.Key]; !ok {
+			keys = append(keys, fp.Key)
+		}
+		byKey[fp.Key] = append(byKey[fp.Key], fp)
+	}
+
+	canonical := map[string]string{}
+	for _, key := range keys {
+		group := byKey[key]
+		if len(group) < 2 {
+			continue
+		}
+
+		// Union-find over the jobs sharing a key, so a duplicate of a
+		// duplicate joins the same group. Roots are the earliest jobs.
+		parent := make([]int, len(group))
+		for i := range parent {
+			parent[i] = i
+		}
+		var find func(int) int
+		find = func(i int) int {
+			if parent[i] != i {
+				parent[i] = find(parent[i])
+			}
+			return parent[i]
+		}
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				if !Duplicates(group[i], group[j]) {
+					continue
+				}
+				ri, rj := find(i), find(j)
+				if ri < rj {
+					parent[rj] = ri
+				} else if rj < ri {
+					parent[ri] = rj
+				}
+			}
+		}
+
+		for i, fp := range group {
+			if root := find(i); root != i {
+				canonical[fp.JobID] = group[root].JobID
+			}
+		}
+	}
+	return canonical
+}
+
+// Words lowercases s and splits it into its letter and digit runs
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Shingles returns the runs of size consecutive words, or the whole text
+// when it is shorter
+func Shingles(words []string, size int) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	if len(words) <= size {
+		return []string{strings.Join(words, " ")}
+	}
+	shingles := make([]string, 0, len(words)-size+1)
+	for i := 0; i+size <= len(words); i++ {
+		shingles = append(shingles, strings.Join(words[i:i+size], " "))
+	}
+	return shingles
+}
+
+// SimHash combines the FNV hashes of features into a 64-bit hash where each
+// bit is set when most features have it set
+func SimHash(features []string) uint64 {
+	var weights [64]int
+	for _, feature := range features {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash
+}
+
+// Distance is the number of bits that differ between two simhashes
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func normalizeCompany(name string) string {
+	words := Words(name)
+	for len(words) > 1 && legalSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// Run regroups every job of store into canonical jobs and duplicates, and
+// returns the number of duplicates
+func Run(store storage.JobStore) (int, error) {
+	jobs, err := store.GetJobsForDedup()
+	if err != nil {
+		return 0, err
+	}
+
+	duplicates := Group(jobs)
+	if err := store.SetCanonicalJobs(duplicates); err != nil {
+		return 0, err
+	}
+	return len(duplicates), nil
+}
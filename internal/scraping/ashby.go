@@ -1,5 +1,21 @@
 package scraping
 
+import "regexp"
+
+func init() {
+	RegisterATS(ATS{
+		Type:               "ashby",
+		CareersURLTemplate: "https://jobs.ashbyhq.com/%s",
+		APIURLTemplate:     "https://api.ashbyhq.com/posting-api/job-board/%s?includeCompensation=true",
+		SlugPattern:        regexp.MustCompile(`^[A-Za-z0-9._%-]+$`),
+		MatchURL:           hostPathMatcher("jobs.ashbyhq.com"),
+		PagePatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)(?:jobs\.ashbyhq\.com|api\.ashbyhq\.com/posting-api/job-board)/([a-z0-9._%-]+)`),
+		},
+		NewScraper: func(atsURL string) Scraper { return NewAshbyScraper(atsURL) },
+	})
+}
+
 type AshbyScraper struct {
 	Url string
 }
@@ -9,6 +25,7 @@ type AshbyResponse struct {
 }
 
 type AshbyJob struct {
+	ID             string             `json:"id"`
 	Title          string             `json:"title"`
 	Location       string             `json:"location"`
 	IsRemote       bool               `json:"isRemote"`
@@ -16,6 +33,8 @@ type AshbyJob struct {
 	ApplyURL       string             `json:"applyUrl"`
 	Description    string             `json:"descriptionHtml"`
 	EmploymentType string             `json:"employmentType"`
+	Department     string             `json:"department"`
+	Team           string             `json:"team"`
 	PublishedAt    string             `json:"publishedAt"`
 	Compensation   *AshbyCompensation `json:"compensation,omitempty"`
 }
@@ -31,9 +50,15 @@ func NewAshbyScraper(atsURL string) AshbyScraper {
 }
 
 func (s AshbyScraper) Scrape() ([]Job, error) {
+	jobs, _, err := s.ScrapeIfChanged(BoardState{})
+	return jobs, err
+}
+
+func (s AshbyScraper) ScrapeIfChanged(state BoardState) ([]Job, BoardState, error) {
 	var ashbyResp AshbyResponse
-	if err := FetchJSON(s.Url, &ashbyResp); err != nil {
-		return nil, err
+	state, err := FetchJSONIfChanged(s.Url, state, &ashbyResp)
+	if err != nil {
+		return nil, state, err
 	}
 
 	jobs := make([]Job, 0, len(ashbyResp.Jobs))
@@ -45,17 +70,27 @@ func (s AshbyScraper) Scrape() ([]Job, error) {
 		if ashbyJob.Compensation != nil {
 			salaryRange = ashbyJob.Compensation.ScrapeableCompensationSalarySummary
 		}
+		department := ashbyJob.Department
+		if department == "" {
+			department = ashbyJob.Team
+		}
 		job := Job{
-			Title:       ashbyJob.Title,
-			Url:         ashbyJob.JobURL,
-			Description: ashbyJob.Description,
-			SalaryRange: salaryRange,
-			Location:    ashbyJob.Location,
-			PublishedAt: ashbyJob.PublishedAt,
+			ExternalID:     ashbyJob.ID,
+			Title:          ashbyJob.Title,
+			Url:            ashbyJob.JobURL,
+			ApplyURL:       ashbyJob.ApplyURL,
+			Description:    ashbyJob.Description,
+			SalaryRange:    salaryRange,
+			Location:       ashbyJob.Location,
+			PublishedAt:    NormalizePublishedAt(ashbyJob.PublishedAt),
+			PublishedAtRaw: ashbyJob.PublishedAt,
+			EmploymentType: NormalizeEmploymentType(ashbyJob.EmploymentType),
+			Department:     department,
 		}
 		jobs = append(jobs, job)
 	}
 
+	jobs = normalizeJobs(jobs)
 	LogScrapeResult(s.Url, len(jobs))
-	return jobs, nil
+	return jobs, state, nil
 }
@@ -19,19 +19,15 @@ func (s UnknownScraper) Scrape() ([]Job, error) {
 
 func CompanyToScraper(company Company) (Scraper, error) {
 	switch company.ATSType {
-	case "ashby":
-		return NewAshbyScraper(company.ATSUrl), nil
-	case "greenhouse":
-		return NewGreenhouseScraper(company.ATSUrl), nil
-	case "recruitee":
-		return NewRecruiteeScraper(company.ATSUrl), nil
-	case "lever":
-		return NewLeverScraper(company.ATSUrl), nil
 	case "custom", "unknown", "":
 		return UnknownScraper{
 			Url: company.ATSUrl,
 		}, nil
-	default:
+	}
+
+	ats, ok := LookupATS(company.ATSType)
+	if !ok {
 		return nil, fmt.Errorf("unknown ATS type: %s", company.ATSType)
 	}
+	return ats.NewScraper(company.ATSUrl), nil
 }
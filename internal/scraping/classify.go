@@ -0,0 +1,184 @@
+package scraping
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+//go:embed classification.yml
+var classificationRules []byte
+
+// defaultClassifier classifies jobs with the embedded rules
+var defaultClassifier = mustNewClassifier(classificationRules)
+
+// ClassificationTag is a value a classification can give a job
+type ClassificationTag struct {
+	Tag   string `yaml:"tag"`
+	Label string `yaml:"label"`
+}
+
+type classificationFile struct {
+	Seniority classificationSpec `yaml:"seniority"`
+	Category  classificationSpec `yaml:"category"`
+}
+
+type classificationSpec struct {
+	Default string              `yaml:"default"`
+	Tags    []ClassificationTag `yaml:"tags"`
+	Rules   []struct {
+		Tag         string   `yaml:"tag"`
+		Title       []string `yaml:"title"`
+		Department  []string `yaml:"department"`
+		Description []string `yaml:"description"`
+	} `yaml:"rules"`
+}
+
+// Classifier tags jobs with a seniority and a role category using rules
+// matched against their title, department and description
+type Classifier struct {
+	seniority classification
+	category  classification
+}
+
+type classification struct {
+	fallback string
+	tags     []ClassificationTag
+	rules    []classificationRule
+}
+
+// classificationRule has a nil pattern for the fields it doesn't match
+type classificationRule struct {
+	tag         string
+	title       *regexp.Regexp
+	department  *regexp.Regexp
+	description *regexp.Regexp
+}
+
+// NewClassifier parses classification rules in the format of the embedded
+// classification.yml
+func NewClassifier(data []byte) (*Classifier, error) {
+	var file classificationFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing classification rules: %w", err)
+	}
+
+	seniority, err := compileClassification(file.Seniority)
+	if err != nil {
+		return nil, fmt.Errorf("compiling seniority rules: %w", err)
+	}
+	category, err := compileClassification(file.Category)
+	if err != nil {
+		return nil, fmt.Errorf("compiling category rules: %w", err)
+	}
+	return &Classifier{seniority: seniority, category: category}, nil
+}
+
+func mustNewClassifier(data []byte) *Classifier {
+	c, err := NewClassifier(data)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func compileClassification(spec classificationSpec) (classification, error) {
+	known := map[string]bool{}
+	for _, tag := range spec.Tags {
+		known[tag.Tag] = true
+	}
+	if spec.Default != "" && !known[spec.Default] {
+		return classification{}, fmt.Errorf("default %q is not in tags", spec.Default)
+	}
+
+	c := classification{fallback: spec.Default, tags: spec.Tags}
+	for _, rule := range spec.Rules {
+		if !known[rule.Tag] {
+			return classification{}, fmt.Errorf("rule tag %q is not in tags", rule.Tag)
+		}
+
+		compiled := classificationRule{tag: rule.Tag}
+		var err error
+		if compiled.title, err = compileWords(rule.Title, true); err != nil {
+			return classification{}, fmt.Errorf("rule %s: %w", rule.Tag, err)
+		}
+		if compiled.department, err = compileWords(rule.Department, true); err != nil {
+			return classification{}, fmt.Errorf("rule %s: %w", rule.Tag, err)
+		}
+		if compiled.description, err = compileWords(rule.Description, true); err != nil {
+			return classification{}, fmt.Errorf("rule %s: %w", rule.Tag, err)
+		}
+		c.rules = append(c.rules, compiled)
+	}
+	return c, nil
+}
+
+// compileWords compiles patterns into one regexp matching any of them as
+// whole words. \b only knows ASCII and word characters, so patterns like
+// ".net" or "r&d" need explicit boundaries.
+func compileWords(patterns []string, ignoreCase bool) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	flags := ""
+	if ignoreCase {
+		flags = "(?i)"
+	}
+	return regexp.Compile(flags + `(?:^|[^\pL\pN])(?:` + strings.Join(patterns, "|") + `)(?:$|[^\pL\pN])`)
+}
+
+// Classify returns the seniority and category of job
+func (c *Classifier) Classify(job Job) (seniority, category string) {
+	return c.seniority.classify(job), c.category.classify(job)
+}
+
+// Seniorities returns the seniority tags in display order
+func (c *Classifier) Seniorities() []ClassificationTag {
+	return c.seniority.tags
+}
+
+// Categories returns the category tags in display order
+func (c *Classifier) Categories() []ClassificationTag {
+	return c.category.tags
+}
+
+func (c classification) classify(job Job) string {
+	fields := []struct {
+		value   string
+		pattern func(classificationRule) *regexp.Regexp
+	}{
+		{job.Title, func(r classificationRule) *regexp.Regexp { return r.title }},
+		{job.Department, func(r classificationRule) *regexp.Regexp { return r.department }},
+		{job.Description, func(r classificationRule) *regexp.Regexp { return r.description }},
+	}
+
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		for _, rule := range c.rules {
+			if pattern := field.pattern(rule); pattern != nil && pattern.MatchString(field.value) {
+				return rule.tag
+			}
+		}
+	}
+	return c.fallback
+}
+
+// Classify returns the seniority and category of job with the embedded rules
+func Classify(job Job) (seniority, category string) {
+	return defaultClassifier.Classify(job)
+}
+
+// Seniorities returns the seniority tags of the embedded rules in display order
+func Seniorities() []ClassificationTag {
+	return defaultClassifier.Seniorities()
+}
+
+// Categories returns the category tags of the embedded rules in display order
+func Categories() []ClassificationTag {
+	return defaultClassifier.Categories()
+}
@@ -1,5 +1,10 @@
 package scraping
 
+import (
+	"errors"
+	"strings"
+)
+
 type Company struct {
 	ID         int64
 	Name       string
@@ -7,7 +12,45 @@ type Company struct {
 	CareersURL string
 	ATSType    string
 	ATSUrl     string
+	Disabled   bool
+	Tags       []string
 	ScrapedAt  string
 	CreatedAt  string
 	UpdatedAt  string
 }
+
+// SplitTags splits tags typed in flags, forms and spreadsheets, separated by
+// commas or semicolons so they survive spreadsheet editing, dropping empty
+// ones
+func SplitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ValidateCompany checks that a company has a name and, when it has an ATS,
+// what is needed to scrape it
+func ValidateCompany(company Company) error {
+	if company.Name == "" {
+		return errors.New("name is required")
+	}
+	if company.ATSType != "" && company.ATSUrl == "" {
+		return errors.New("ATS URL is required when an ATS type is set")
+	}
+	_, err := CompanyToScraper(company)
+	return err
+}
+
+// PreviewScrape scrapes a company without saving anything, so its settings
+// can be checked before it is saved
+func PreviewScrape(company Company) ([]Job, error) {
+	scraper, err := CompanyToScraper(company)
+	if err != nil {
+		return nil, err
+	}
+	return scraper.Scrape()
+}
@@ -0,0 +1,103 @@
+package scraping
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrBoardUnchanged is returned by ScrapeIfChanged when the board is the
+// same as when its BoardState was recorded
+var ErrBoardUnchanged = errors.New("board unchanged since last scrape")
+
+// BoardState is what fetching a board leaves to make the next fetch
+// conditional. Its zero value fetches and parses the board unconditionally.
+type BoardState struct {
+	// URL is the board the state was recorded for, state recorded for
+	// another URL is ignored
+	URL          string
+	ETag         string
+	LastModified string
+	// ContentHash is the SHA-256 of the response body, for the ATSs that
+	// don't send validators or ignore them
+	ContentHash string
+}
+
+// ConditionalScraper is a Scraper able to skip boards which haven't changed
+// since a previous scrape
+type ConditionalScraper interface {
+	Scraper
+	// ScrapeIfChanged scrapes the board unless it is unchanged since state,
+	// in which case it returns ErrBoardUnchanged. It returns the state to
+	// give the next call either way.
+	ScrapeIfChanged(state BoardState) ([]Job, BoardState, error)
+}
+
+// ScrapeIfChanged scrapes with scraper unless the board is unchanged since
+// state, falling back to a full scrape, and no state, for scrapers which
+// can't tell
+func ScrapeIfChanged(scraper Scraper, state BoardState) ([]Job, BoardState, error) {
+	if conditional, ok := scraper.(ConditionalScraper); ok {
+		return conditional.ScrapeIfChanged(state)
+	}
+	jobs, err := scraper.Scrape()
+	return jobs, BoardState{}, err
+}
+
+// FetchJSONIfChanged decodes the JSON at url into target, sending the
+// validators of state, unless the server answers 304 Not Modified or the
+// body hashes the same as before, in which case it returns
+// ErrBoardUnchanged without decoding
+func FetchJSONIfChanged(url string, state BoardState, target interface{}) (BoardState, error) {
+	if state.URL != url {
+		state = BoardState{}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return state, fmt.Errorf("creating request: %w", err)
+	}
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return state, fmt.Errorf("getting %v: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return state, ErrBoardUnchanged
+	}
+	if err := ValidateResponse(resp); err != nil {
+		return state, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return state, fmt.Errorf("reading response: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	next := BoardState{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentHash:  hex.EncodeToString(sum[:]),
+	}
+	if state.ContentHash == next.ContentHash {
+		return next, ErrBoardUnchanged
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return state, fmt.Errorf("decoding JSON response: %w", err)
+	}
+	return next, nil
+}
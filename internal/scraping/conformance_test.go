@@ -0,0 +1,262 @@
+package scraping
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// conformanceJob is a job the conformance suite lists on the boards it
+// serves, whatever their ATS
+type conformanceJob struct {
+	id     int
+	title  string
+	url    string
+	remote bool
+	// full sets every optional field the ATS has, the others are left out
+	full bool
+}
+
+// conformanceBoard is how an ATS lays out a board
+type conformanceBoard struct {
+	// encode returns the API response listing jobs
+	encode func(jobs []conformanceJob) any
+	// filtersRemote is true for the ATSs which say whether jobs are remote,
+	// whose scrapers must leave the others out
+	filtersRemote bool
+}
+
+// conformanceBoards has the board of every registered ATS. A new adapter
+// runs the suite once it adds its own.
+var conformanceBoards = map[string]conformanceBoard{
+	"ashby": {
+		filtersRemote: true,
+		encode: func(jobs []conformanceJob) any {
+			listed := []map[string]any{}
+			for _, job := range jobs {
+				entry := map[string]any{"id": strconv.Itoa(job.id), "title": job.title, "jobUrl": job.url, "isRemote": job.remote}
+				if job.full {
+					entry["applyUrl"] = job.url + "/application"
+					entry["descriptionHtml"] = "<p>" + conformanceDescription + "</p>"
+					entry["employmentType"] = "FullTime"
+					entry["department"] = "Engineering"
+					entry["location"] = "Remote"
+					entry["publishedAt"] = "2024-05-01T10:00:00.000+00:00"
+					entry["compensation"] = map[string]any{"scrapeableCompensationSalarySummary": "€80K - €100K"}
+				}
+				listed = append(listed, entry)
+			}
+			return map[string]any{"jobs": listed}
+		},
+	},
+	"greenhouse": {
+		encode: func(jobs []conformanceJob) any {
+			listed := []map[string]any{}
+			for _, job := range jobs {
+				entry := map[string]any{"id": job.id, "title": job.title, "absolute_url": job.url}
+				if job.full {
+					entry["content"] = "&lt;p&gt;" + conformanceDescription + "&lt;/p&gt;"
+					entry["updated_at"] = "2024-05-01T10:00:00-04:00"
+					entry["location"] = map[string]any{"name": "Remote"}
+					entry["departments"] = []map[string]any{{"name": "Engineering"}}
+				}
+				listed = append(listed, entry)
+			}
+			return map[string]any{"jobs": listed}
+		},
+	},
+	"lever": {
+		filtersRemote: true,
+		encode: func(jobs []conformanceJob) any {
+			listed := []map[string]any{}
+			for _, job := range jobs {
+				workplaceType := "on-site"
+				if job.remote {
+					workplaceType = "remote"
+				}
+				entry := map[string]any{"id": strconv.Itoa(job.id), "text": job.title, "hostedUrl": job.url, "workplaceType": workplaceType}
+				if job.full {
+					entry["applyUrl"] = job.url + "/apply"
+					entry["description"] = "<div>" + conformanceDescription + "</div>"
+					entry["createdAt"] = 1714557600000
+					entry["categories"] = map[string]any{"commitment": "Full-time", "department": "Engineering", "location": "Remote"}
+					entry["salaryRange"] = map[string]any{"currency": "EUR", "interval": "per-year-salary", "min": 80000, "max": 100000}
+				}
+				listed = append(listed, entry)
+			}
+			return listed
+		},
+	},
+	"recruitee": {
+		filtersRemote: true,
+		encode: func(jobs []conformanceJob) any {
+			listed := []map[string]any{}
+			for _, job := range jobs {
+				entry := map[string]any{"id": job.id, "title": job.title, "careers_url": job.url, "remote": job.remote}
+				if job.full {
+					entry["careers_apply_url"] = job.url + "/c/new"
+					entry["description"] = "<p>" + conformanceDescription + "</p>"
+					entry["location"] = map[string]any{"city": "Amsterdam"}
+					entry["department"] = "Engineering"
+					entry["employment_type_code"] = "fulltime_permanent"
+					entry["created_at"] = "2024-05-01 10:00:00 UTC"
+					entry["salary"] = map[string]any{"min": "4000", "max": "5500", "period": "month", "currency": "EUR"}
+				}
+				listed = append(listed, entry)
+			}
+			return map[string]any{"offers": listed}
+		},
+	},
+}
+
+var conformanceDescription = strings.Repeat("Build and run the services behind our product. ", 20)
+
+func TestScraperConformance(t *testing.T) {
+	for _, ats := range RegisteredATS() {
+		board, ok := conformanceBoards[ats.Type]
+		if !ok {
+			t.Errorf("ATS %q has no conformance board, add one to conformanceBoards", ats.Type)
+			continue
+		}
+		t.Run(ats.Type, func(t *testing.T) {
+			runConformance(t, ats.NewScraper, board)
+		})
+	}
+}
+
+// runConformance checks what every Scraper must do against the boards it is
+// served, laid out as board says
+func runConformance(t *testing.T, newScraper func(url string) Scraper, board conformanceBoard) {
+	scrape := func(t *testing.T, status int, body []byte) ([]Job, error) {
+		t.Helper()
+		return newScraper(serveResponse(t, status, body)).Scrape()
+	}
+	scrapeBoard := func(t *testing.T, jobs ...conformanceJob) []Job {
+		t.Helper()
+		body, err := json.Marshal(board.encode(jobs))
+		if err != nil {
+			t.Fatalf("encoding board: %v", err)
+		}
+		scraped, err := scrape(t, http.StatusOK, body)
+		if err != nil {
+			t.Fatalf("scraping: %v", err)
+		}
+		return scraped
+	}
+
+	t.Run("non-200 responses fail", func(t *testing.T) {
+		body, _ := json.Marshal(board.encode([]conformanceJob{remoteJob(1)}))
+		for _, status := range []int{http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError} {
+			if _, err := scrape(t, status, body); err == nil {
+				t.Errorf("status %d: got no error", status)
+			}
+		}
+	})
+
+	t.Run("malformed JSON fails", func(t *testing.T) {
+		for _, body := range []string{`{"jobs": [`, `<html>Not found</html>`, ``} {
+			if _, err := scrape(t, http.StatusOK, []byte(body)); err == nil {
+				t.Errorf("body %q: got no error", body)
+			}
+		}
+	})
+
+	t.Run("empty board", func(t *testing.T) {
+		if jobs := scrapeBoard(t); len(jobs) != 0 {
+			t.Errorf("got %d jobs, want none", len(jobs))
+		}
+	})
+
+	t.Run("huge board", func(t *testing.T) {
+		const size = 5000
+		listed := make([]conformanceJob, size)
+		for i := range listed {
+			listed[i] = remoteJob(i + 1)
+			listed[i].full = true
+		}
+		jobs := scrapeBoard(t, listed...)
+		if len(jobs) != size {
+			t.Fatalf("got %d jobs, want %d", len(jobs), size)
+		}
+		if jobs[size-1].Title != listed[size-1].title {
+			t.Errorf("last job title = %q, want %q", jobs[size-1].Title, listed[size-1].title)
+		}
+	})
+
+	t.Run("HTML entities in titles", func(t *testing.T) {
+		job := remoteJob(1)
+		job.title = "  R&amp;D Engineer &#8211; &quot;Core&quot;\n"
+		jobs := scrapeBoard(t, job)
+		if len(jobs) != 1 {
+			t.Fatalf("got %d jobs, want 1", len(jobs))
+		}
+		if want := `R&D Engineer – "Core"`; jobs[0].Title != want {
+			t.Errorf("title = %q, want %q", jobs[0].Title, want)
+		}
+	})
+
+	t.Run("missing optional fields", func(t *testing.T) {
+		jobs := scrapeBoard(t, remoteJob(7))
+		if len(jobs) != 1 {
+			t.Fatalf("got %d jobs, want 1", len(jobs))
+		}
+		if jobs[0].ExternalID != "7" {
+			t.Errorf("external ID = %q, want %q", jobs[0].ExternalID, "7")
+		}
+	})
+
+	t.Run("all optional fields", func(t *testing.T) {
+		job := remoteJob(1)
+		job.full = true
+		jobs := scrapeBoard(t, job)
+		if len(jobs) != 1 {
+			t.Fatalf("got %d jobs, want 1", len(jobs))
+		}
+		if !strings.Contains(DescriptionText(jobs[0].Description), strings.TrimSpace(conformanceDescription)) {
+			t.Errorf("description %q doesn't have the listed one", jobs[0].Description)
+		}
+		if jobs[0].PublishedAt == "" {
+			t.Errorf("published date is empty")
+		}
+	})
+
+	t.Run("non-remote jobs are filtered", func(t *testing.T) {
+		onSite := remoteJob(2)
+		onSite.remote = false
+		jobs := scrapeBoard(t, remoteJob(1), onSite)
+		want := 2
+		if board.filtersRemote {
+			want = 1
+		}
+		if len(jobs) != want {
+			t.Errorf("got %d jobs, want %d", len(jobs), want)
+		}
+	})
+
+	t.Run("required fields are populated", func(t *testing.T) {
+		untitled, unlinked := remoteJob(2), remoteJob(3)
+		untitled.title = " "
+		unlinked.url = ""
+		jobs := scrapeBoard(t, remoteJob(1), untitled, unlinked)
+		if len(jobs) != 1 {
+			t.Fatalf("got %d jobs, want only the complete one", len(jobs))
+		}
+		for _, job := range jobs {
+			if job.Title == "" || job.Url == "" {
+				t.Errorf("job %+v is missing its title or URL", job)
+			}
+		}
+	})
+}
+
+// remoteJob returns a remote job with only the fields every ATS requires
+func remoteJob(id int) conformanceJob {
+	return conformanceJob{
+		id:     id,
+		title:  "Backend Engineer " + strconv.Itoa(id),
+		url:    "https://jobs.example.com/" + strconv.Itoa(id),
+		remote: true,
+	}
+}
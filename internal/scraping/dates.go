@@ -0,0 +1,54 @@
+package scraping
+
+import (
+	"strconv"
+	"time"
+)
+
+// publishedAtLayouts are the date formats ATSs report, tried in order
+var publishedAtLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	// Recruitee, e.g. "2024-03-09 10:38:13 UTC"
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParsePublishedAt parses a date reported by an ATS, in one of
+// publishedAtLayouts or as Unix milliseconds. Dates outside the years
+// RFC3339 can represent are rejected.
+func ParsePublishedAt(raw string) (time.Time, bool) {
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if millis, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return inRFC3339Range(time.UnixMilli(millis).UTC())
+	}
+	for _, layout := range publishedAtLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return inRFC3339Range(t.UTC())
+		}
+	}
+	return time.Time{}, false
+}
+
+func inRFC3339Range(t time.Time) (time.Time, bool) {
+	if t.Year() < 0 || t.Year() > 9999 {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// NormalizePublishedAt formats a date reported by an ATS as UTC RFC3339,
+// which sorts chronologically as text, or returns "" when it can't be parsed
+func NormalizePublishedAt(raw string) string {
+	t, ok := ParsePublishedAt(raw)
+	if !ok {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
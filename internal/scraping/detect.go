@@ -0,0 +1,170 @@
+package scraping
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrATSNotDetected is returned when no supported ATS could be recognized
+var ErrATSNotDetected = errors.New("could not detect ATS")
+
+// maxCareersPageSize caps how much of a careers page is scanned for ATS embeds
+const maxCareersPageSize = 2 << 20
+
+var detectClient = &http.Client{Timeout: 15 * time.Second}
+
+// DetectedATS describes the ATS board behind a careers page
+type DetectedATS struct {
+	Type string
+	Slug string
+	// URL is the API endpoint to scrape, suitable for Company.ATSUrl
+	URL string
+}
+
+// ignoredSlugs are path segments or subdomains that appear in ATS URLs but never name a board
+var ignoredSlugs = map[string]bool{
+	"api": true, "app": true, "cdn": true, "www": true, "embed": true, "static": true, "assets": true,
+}
+
+// DetectATS recognizes the ATS behind a careers page. It first matches the
+// URL itself, then fetches the page and looks for embedded boards, and
+// finally probes each ATS API with a slug guessed from the domain.
+func DetectATS(careersURL string) (DetectedATS, error) {
+	if detected, ok := DetectATSFromURL(careersURL); ok {
+		return detected, nil
+	}
+
+	detected, err := detectFromPage(careersURL)
+	if err == nil {
+		return detected, nil
+	}
+	if !errors.Is(err, ErrATSNotDetected) {
+		return DetectedATS{}, err
+	}
+
+	return probeATSAPIs(careersURL)
+}
+
+// DetectATSFromURL recognizes the ATS hosting a careers page from its URL
+// alone, without any network access. ok is false when the URL does not
+// match any registered ATS.
+func DetectATSFromURL(careersURL string) (detected DetectedATS, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(careersURL))
+	if err != nil || u.Host == "" {
+		return DetectedATS{}, false
+	}
+
+	for _, ats := range RegisteredATS() {
+		if ats.MatchURL == nil {
+			continue
+		}
+		if slug, ok := ats.MatchURL(u); ok && isBoardSlug(ats, slug) {
+			return newDetectedATS(ats, slug), true
+		}
+	}
+
+	return DetectedATS{}, false
+}
+
+func newDetectedATS(ats ATS, slug string) DetectedATS {
+	return DetectedATS{
+		Type: ats.Type,
+		Slug: slug,
+		URL:  ats.APIURL(slug),
+	}
+}
+
+func isBoardSlug(ats ATS, slug string) bool {
+	return !ignoredSlugs[strings.ToLower(slug)] && ats.ValidateSlug(slug) == nil
+}
+
+// detectFromPage fetches the careers page and looks for the ATS in the final
+// URL after redirects and in the page content
+func detectFromPage(careersURL string) (DetectedATS, error) {
+	resp, err := detectClient.Get(careersURL)
+	if err != nil {
+		return DetectedATS{}, fmt.Errorf("getting %v: %w", careersURL, err)
+	}
+	defer resp.Body.Close()
+
+	if detected, ok := DetectATSFromURL(resp.Request.URL.String()); ok {
+		return detected, nil
+	}
+
+	if err := ValidateResponse(resp); err != nil {
+		return DetectedATS{}, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCareersPageSize))
+	if err != nil {
+		return DetectedATS{}, fmt.Errorf("reading %v: %w", careersURL, err)
+	}
+
+	for _, ats := range RegisteredATS() {
+		for _, pattern := range ats.PagePatterns {
+			for _, match := range pattern.FindAllSubmatch(body, -1) {
+				if slug := string(match[1]); isBoardSlug(ats, slug) {
+					return newDetectedATS(ats, slug), nil
+				}
+			}
+		}
+	}
+
+	return DetectedATS{}, ErrATSNotDetected
+}
+
+// probeATSAPIs guesses the board slug from the careers page domain
+// (careers.acme.com -> acme) and checks which ATS API knows about it
+func probeATSAPIs(careersURL string) (DetectedATS, error) {
+	slug := slugFromHost(careersURL)
+	if slug == "" {
+		return DetectedATS{}, ErrATSNotDetected
+	}
+
+	for _, ats := range RegisteredATS() {
+		if !isBoardSlug(ats, slug) {
+			continue
+		}
+		detected := newDetectedATS(ats, slug)
+		resp, err := detectClient.Get(detected.URL)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return detected, nil
+		}
+	}
+
+	return DetectedATS{}, ErrATSNotDetected
+}
+
+func slugFromHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	labels := strings.Split(strings.ToLower(u.Hostname()), ".")
+	if len(labels) < 2 {
+		return ""
+	}
+	// Second-level public suffixes such as acme.co.uk
+	if len(labels) >= 3 && secondLevelSuffixes[labels[len(labels)-2]] {
+		return labels[len(labels)-3]
+	}
+	return labels[len(labels)-2]
+}
+
+var secondLevelSuffixes = map[string]bool{
+	"co": true, "com": true, "org": true, "net": true, "ac": true, "gov": true,
+}
+
+func firstPathSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.Trim(path, "/"), "/")
+	return segment
+}
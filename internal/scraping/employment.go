@@ -0,0 +1,43 @@
+package scraping
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Employment types jobs are normalized to, so they can be filtered across ATSs
+const (
+	EmploymentFullTime   = "full-time"
+	EmploymentPartTime   = "part-time"
+	EmploymentContract   = "contract"
+	EmploymentInternship = "internship"
+)
+
+// EmploymentTypes lists the normalized employment types in display order
+var EmploymentTypes = []string{EmploymentFullTime, EmploymentPartTime, EmploymentContract, EmploymentInternship}
+
+// NormalizeEmploymentType maps the employment type of an ATS, e.g. Ashby's
+// "FullTime", Lever's "Full-time" commitment or Recruitee's
+// "fulltime_permanent", to one of EmploymentTypes, or "" if it is unknown
+func NormalizeEmploymentType(raw string) string {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, raw)
+
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "intern") || strings.Contains(s, "trainee") || strings.Contains(s, "apprentice"):
+		return EmploymentInternship
+	case strings.Contains(s, "part"):
+		return EmploymentPartTime
+	case strings.Contains(s, "contract") || strings.Contains(s, "freelance") || strings.Contains(s, "temporary"):
+		return EmploymentContract
+	case strings.Contains(s, "full") || strings.Contains(s, "permanent"):
+		return EmploymentFullTime
+	}
+	return ""
+}
@@ -0,0 +1,13 @@
+package scraping
+
+// Enrich fills in the fields of scraped jobs derived from the ones the ATS
+// reports, before they are saved
+func Enrich(jobs []Job) {
+	for i := range jobs {
+		jobs[i].Seniority, jobs[i].Category = Classify(jobs[i])
+		jobs[i].Tags = ExtractSkills(jobs[i])
+		jobs[i].Language = DetectLanguage(jobs[i].Title + "\n" + DescriptionText(jobs[i].Description))
+		jobs[i].Signals = ExtractSignals(jobs[i])
+		jobs[i].Timezone = ParseTimezoneWindow(jobs[i])
+	}
+}
@@ -0,0 +1,256 @@
+package scraping
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureValues returns the raw JSON of every value under one of keys in the
+// ATS responses of testdata, for the fuzz targets to seed their corpus with
+// what real boards send
+func fixtureValues(f *testing.F, keys ...string) [][]byte {
+	f.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "*", "*.json"))
+	if err != nil {
+		f.Fatalf("listing fixtures: %v", err)
+	}
+
+	var values [][]byte
+	var collect func(node any)
+	collect = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			for key, value := range node {
+				if slices.Contains(keys, key) {
+					raw, err := json.Marshal(value)
+					if err != nil {
+						f.Fatalf("encoding %s: %v", key, err)
+					}
+					values = append(values, raw)
+				}
+				collect(value)
+			}
+		case []any:
+			for _, value := range node {
+				collect(value)
+			}
+		}
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".golden.json") {
+			continue
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatalf("reading fixture: %v", err)
+		}
+		var decoded any
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			f.Fatalf("decoding %s: %v", path, err)
+		}
+		collect(decoded)
+	}
+	if len(values) == 0 {
+		f.Fatalf("no fixture has any of %v", keys)
+	}
+	return values
+}
+
+// fixtureStrings is fixtureValues for the values which are strings
+func fixtureStrings(f *testing.F, keys ...string) []string {
+	f.Helper()
+	var values []string
+	for _, raw := range fixtureValues(f, keys...) {
+		var value string
+		if json.Unmarshal(raw, &value) == nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func FuzzRecruiteeSalary(f *testing.F) {
+	for _, raw := range fixtureValues(f, "salary") {
+		f.Add(raw)
+	}
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"min": 4000}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var salary RecruiteeSalary
+		if err := salary.UnmarshalJSON(data); err != nil {
+			return
+		}
+
+		var str string
+		if json.Unmarshal(data, &str) == nil && string(salary) != str {
+			t.Errorf("string salary %q decoded as %q", str, salary)
+		}
+
+		// The formatted salary is kept as is when read back as a string
+		encoded, err := json.Marshal(string(salary))
+		if err != nil {
+			t.Fatalf("encoding %q: %v", salary, err)
+		}
+		var decoded RecruiteeSalary
+		if err := decoded.UnmarshalJSON(encoded); err != nil {
+			t.Fatalf("decoding %s: %v", encoded, err)
+		}
+		if decoded != salary {
+			t.Errorf("salary %q round-tripped as %q", salary, decoded)
+		}
+	})
+}
+
+func FuzzRecruiteeLocation(f *testing.F) {
+	for _, raw := range fixtureValues(f, "location") {
+		f.Add(raw)
+	}
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"city": 12}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var location RecruiteeLocation
+		if err := location.UnmarshalJSON(data); err != nil {
+			return
+		}
+
+		var str string
+		if json.Unmarshal(data, &str) == nil && location.City != str {
+			t.Errorf("string location %q decoded as %q", str, location.City)
+		}
+
+		// Both layouts of the same city decode to it
+		asString, err := json.Marshal(location.City)
+		if err != nil {
+			t.Fatalf("encoding %q: %v", location.City, err)
+		}
+		asObject, err := json.Marshal(map[string]string{"city": location.City})
+		if err != nil {
+			t.Fatalf("encoding %q: %v", location.City, err)
+		}
+		for _, encoded := range [][]byte{asString, asObject} {
+			var decoded RecruiteeLocation
+			if err := decoded.UnmarshalJSON(encoded); err != nil {
+				t.Fatalf("decoding %s: %v", encoded, err)
+			}
+			if decoded != location {
+				t.Errorf("location %q round-tripped through %s as %q", location.City, encoded, decoded.City)
+			}
+		}
+	})
+}
+
+func FuzzFormatLeverSalary(f *testing.F) {
+	for _, raw := range fixtureValues(f, "salaryRange") {
+		var salary LeverSalaryRange
+		if err := json.Unmarshal(raw, &salary); err != nil {
+			f.Fatalf("decoding %s: %v", raw, err)
+		}
+		f.Add(salary.Currency, salary.Interval, deref(salary.Min), deref(salary.Max), salary.Min != nil, salary.Max != nil)
+	}
+	f.Add("", "", 0.0, 0.0, false, false)
+
+	f.Fuzz(func(t *testing.T, currency, interval string, minimum, maximum float64, hasMin, hasMax bool) {
+		salary := &LeverSalaryRange{Currency: currency, Interval: interval}
+		if hasMin {
+			salary.Min = &minimum
+		}
+		if hasMax {
+			salary.Max = &maximum
+		}
+		formatted := formatLeverSalary(salary)
+
+		if empty := currency == "" && interval == "" && !hasMin && !hasMax; empty != (formatted == "") {
+			t.Errorf("salary %+v formatted as %q", salary, formatted)
+		}
+		if !strings.HasPrefix(formatted, currency) || !strings.HasSuffix(formatted, interval) {
+			t.Errorf("salary %+v formatted as %q, without its currency or interval", salary, formatted)
+		}
+		if hasMin && hasMax && !strings.Contains(formatted, " - ") {
+			t.Errorf("salary %+v formatted as %q, without its range", salary, formatted)
+		}
+	})
+}
+
+func deref(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func FuzzNormalizePublishedAt(f *testing.F) {
+	for _, raw := range fixtureStrings(f, "publishedAt", "updated_at", "created_at") {
+		f.Add(raw)
+	}
+	for _, raw := range fixtureValues(f, "createdAt") {
+		f.Add(string(raw))
+	}
+	f.Add("Sat, 09 Mar 2024 10:38:13 +0100")
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		normalized := NormalizePublishedAt(raw)
+		if normalized == "" {
+			return
+		}
+
+		parsed, err := time.Parse(time.RFC3339, normalized)
+		if err != nil {
+			t.Fatalf("%q normalized as %q, which isn't RFC3339: %v", raw, normalized, err)
+		}
+		if parsed.Location() != time.UTC {
+			t.Errorf("%q normalized as %q, which isn't UTC", raw, normalized)
+		}
+		if again := NormalizePublishedAt(normalized); again != normalized {
+			t.Errorf("%q normalized as %q, then as %q", raw, normalized, again)
+		}
+	})
+}
+
+func FuzzNormalizeEmploymentType(f *testing.F) {
+	for _, raw := range fixtureStrings(f, "employmentType", "employment_type_code", "commitment") {
+		f.Add(raw)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		normalized := NormalizeEmploymentType(raw)
+		if normalized == "" {
+			return
+		}
+		if !slices.Contains(EmploymentTypes, normalized) {
+			t.Fatalf("%q normalized as unknown type %q", raw, normalized)
+		}
+		if again := NormalizeEmploymentType(normalized); again != normalized {
+			t.Errorf("%q normalized as %q, then as %q", raw, normalized, again)
+		}
+	})
+}
+
+func FuzzParseTimezoneWindow(f *testing.F) {
+	descriptions := fixtureStrings(f, "descriptionHtml", "content", "description")
+	locations := fixtureStrings(f, "location", "name")
+	for i := range max(len(descriptions), len(locations)) {
+		f.Add(descriptions[i%len(descriptions)], locations[i%len(locations)])
+	}
+	f.Add("<p>You overlap at least 4 hours with CET ± 3 hours.</p>", "Remote")
+	f.Add("<p>Work between UTC-3 and UTC+5:30.</p>", "Remote - Americas")
+
+	f.Fuzz(func(t *testing.T, description, location string) {
+		window := ParseTimezoneWindow(Job{Description: description, Location: location})
+		if window == nil {
+			return
+		}
+		if window.Min > window.Max {
+			t.Errorf("description %q and location %q give window %+v, which is empty", description, location, *window)
+		}
+		if window.String() == "" {
+			t.Errorf("window %+v formats as nothing", *window)
+		}
+	})
+}
@@ -1,6 +1,37 @@
 // https://developers.greenhouse.io/job-board.html#list-jobs
 package scraping
 
+import (
+	"net/url"
+	"regexp"
+)
+
+func init() {
+	RegisterATS(ATS{
+		Type:               "greenhouse",
+		CareersURLTemplate: "https://job-boards.greenhouse.io/%s",
+		APIURLTemplate:     "https://boards-api.greenhouse.io/v1/boards/%s/jobs?content=true",
+		SlugPattern:        regexp.MustCompile(`^[A-Za-z0-9_-]+$`),
+		MatchURL:           matchGreenhouseURL,
+		PagePatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)(?:boards|job-boards)\.greenhouse\.io/(?:embed/job_board(?:/js)?\?for=)?([a-z0-9_-]+)`),
+			regexp.MustCompile(`(?i)boards-api\.greenhouse\.io/v1/boards/([a-z0-9_-]+)`),
+		},
+		NewScraper: func(atsURL string) Scraper { return NewGreenhouseScraper(atsURL) },
+	})
+}
+
+// matchGreenhouseURL handles both hosted boards and the embed URL, which
+// passes the slug as ?for=<slug>
+func matchGreenhouseURL(u *url.URL) (string, bool) {
+	slug, ok := hostPathMatcher("job-boards.greenhouse.io", "boards.greenhouse.io")(u)
+	if ok && slug == "embed" {
+		slug = u.Query().Get("for")
+		ok = slug != ""
+	}
+	return slug, ok
+}
+
 type GreenhouseScraper struct {
 	Url string
 }
@@ -10,18 +41,23 @@ type GreenhouseResponse struct {
 }
 
 type GreenhouseJob struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Location    *Location `json:"location"`
-	Content     string    `json:"content"`
-	UpdatedAt   string    `json:"updated_at"`
-	AbsoluteURL string    `json:"absolute_url"`
+	ID          int64                  `json:"id"`
+	Title       string                 `json:"title"`
+	Location    *Location              `json:"location"`
+	Content     string                 `json:"content"`
+	UpdatedAt   string                 `json:"updated_at"`
+	AbsoluteURL string                 `json:"absolute_url"`
+	Departments []GreenhouseDepartment `json:"departments"`
 }
 
 type Location struct {
 	Name string `json:"name"`
 }
 
+type GreenhouseDepartment struct {
+	Name string `json:"name"`
+}
+
 func NewGreenhouseScraper(atsURL string) GreenhouseScraper {
 	return GreenhouseScraper{
 		Url: atsURL,
@@ -29,10 +65,16 @@ func NewGreenhouseScraper(atsURL string) GreenhouseScraper {
 }
 
 func (s GreenhouseScraper) Scrape() ([]Job, error) {
+	jobs, _, err := s.ScrapeIfChanged(BoardState{})
+	return jobs, err
+}
+
+func (s GreenhouseScraper) ScrapeIfChanged(state BoardState) ([]Job, BoardState, error) {
 	// ats_url should contain the full JSON API endpoint
 	var greenhouseResp GreenhouseResponse
-	if err := FetchJSON(s.Url, &greenhouseResp); err != nil {
-		return nil, err
+	state, err := FetchJSONIfChanged(s.Url, state, &greenhouseResp)
+	if err != nil {
+		return nil, state, err
 	}
 
 	jobs := make([]Job, 0, len(greenhouseResp.Jobs))
@@ -41,17 +83,26 @@ func (s GreenhouseScraper) Scrape() ([]Job, error) {
 		if greenhouseJob.Location != nil {
 			location = greenhouseJob.Location.Name
 		}
+		// A job can belong to several departments, only the first is kept
+		department := ""
+		if len(greenhouseJob.Departments) > 0 {
+			department = greenhouseJob.Departments[0].Name
+		}
 		job := Job{
-			Title:       greenhouseJob.Title,
-			Url:         greenhouseJob.AbsoluteURL,
-			Description: greenhouseJob.Content,
-			PublishedAt: greenhouseJob.UpdatedAt,
-			SalaryRange: "",
-			Location:    location,
+			ExternalID:     formatID(greenhouseJob.ID),
+			Title:          greenhouseJob.Title,
+			Url:            greenhouseJob.AbsoluteURL,
+			Description:    greenhouseJob.Content,
+			PublishedAt:    NormalizePublishedAt(greenhouseJob.UpdatedAt),
+			PublishedAtRaw: greenhouseJob.UpdatedAt,
+			SalaryRange:    "",
+			Location:       location,
+			Department:     department,
 		}
 		jobs = append(jobs, job)
 	}
 
+	jobs = normalizeJobs(jobs)
 	LogScrapeResult(s.Url, len(jobs))
-	return jobs, nil
+	return jobs, state, nil
 }
@@ -3,14 +3,39 @@ package scraping
 import "time"
 
 type Job struct {
-	ID          string
-	Url         string
+	ID string
+	// ExternalID is the job's ID in its ATS, which identifies it even when
+	// the ATS changes its public URL format
+	ExternalID string
+	Url        string
+	// ApplyURL is the application form, when the ATS has one besides Url
+	ApplyURL    string
 	Description string
 	Title       string
 	SalaryRange string
 	Location    string
+	// PublishedAt is in UTC RFC3339, as given by NormalizePublishedAt
 	PublishedAt string
-	Company     *Company
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// PublishedAtRaw is the date as the ATS reported it, kept so it can be
+	// parsed again
+	PublishedAtRaw string
+	Company        *Company
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	// EmploymentType is one of EmploymentTypes, or "" when the ATS doesn't say
+	EmploymentType string
+	Department     string
+	// Seniority and Category are tags given by Classify
+	Seniority string
+	Category  string
+	// Tags are the Skill tags the job mentions
+	Tags []string
+	// Language is the ISO 639-1 code given by DetectLanguage
+	Language string
+	// Signals are the names of the Signals the description mentions
+	Signals []string
+	// Timezone is the window given by ParseTimezoneWindow, nil when the job
+	// doesn't say where candidates should work from
+	Timezone *TimezoneWindow
 }
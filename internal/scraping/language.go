@@ -0,0 +1,118 @@
+package scraping
+
+import (
+	"embed"
+	"math"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// languageSamples holds a sample job posting per language, named after its
+// ISO 639-1 code. Adding a language only takes adding a sample.
+//
+//go:embed languages/*.txt
+var languageSamples embed.FS
+
+// Language is a language job postings are detected in
+type Language struct {
+	// Code is the ISO 639-1 code, e.g. "de"
+	Code string
+	Name string
+}
+
+// Languages lists the languages DetectLanguage knows, in display order
+var Languages = []Language{
+	{"en", "English"},
+	{"de", "German"},
+	{"fr", "French"},
+	{"nl", "Dutch"},
+	{"es", "Spanish"},
+	{"it", "Italian"},
+	{"pt", "Portuguese"},
+}
+
+const (
+	// minLanguageTrigrams is the number of trigrams below which a text is
+	// too short to tell its language
+	minLanguageTrigrams = 12
+	// maxLanguageTrigrams caps how much of a long description is scored
+	maxLanguageTrigrams = 2000
+)
+
+// languageModel holds the trigram counts of a language sample
+type languageModel struct {
+	code   string
+	counts map[string]int
+	total  int
+}
+
+var languageModels = mustLoadLanguageModels()
+
+func mustLoadLanguageModels() []languageModel {
+	models := make([]languageModel, 0, len(Languages))
+	for _, language := range Languages {
+		sample, err := languageSamples.ReadFile(path.Join("languages", language.Code+".txt"))
+		if err != nil {
+			panic(err)
+		}
+
+		model := languageModel{code: language.Code, counts: map[string]int{}}
+		for _, trigram := range trigrams(string(sample), 0) {
+			model.counts[trigram]++
+			model.total++
+		}
+		models = append(models, model)
+	}
+	return models
+}
+
+// trigrams returns the letter trigrams of the lowercased words of text,
+// padded with spaces so word starts and ends count, up to limit if not 0
+func trigrams(text string, limit int) []string {
+	var result []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result = append(result, string(runes[i:i+3]))
+			if limit > 0 && len(result) >= limit {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// DetectLanguage returns the ISO 639-1 code of the language of text, or ""
+// when it is too short to tell. Each language scores the likelihood of the
+// text's trigrams under its sample, with add-one smoothing.
+func DetectLanguage(text string) string {
+	grams := trigrams(text, maxLanguageTrigrams)
+	if len(grams) < minLanguageTrigrams {
+		return ""
+	}
+
+	best, bestScore := "", math.Inf(-1)
+	for _, model := range languageModels {
+		var score float64
+		denominator := math.Log(float64(model.total + len(model.counts) + 1))
+		for _, gram := range grams {
+			score += math.Log(float64(model.counts[gram]+1)) - denominator
+		}
+		if score > bestScore {
+			best, bestScore = model.code, score
+		}
+	}
+	return best
+}
+
+// LanguageName returns the English name of a language code, or the code
+// itself if it isn't one of Languages
+func LanguageName(code string) string {
+	for _, language := range Languages {
+		if language.Code == code {
+			return language.Name
+		}
+	}
+	return code
+}
@@ -3,9 +3,26 @@ package scraping
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
+func init() {
+	RegisterATS(ATS{
+		Type:               "lever",
+		CareersURLTemplate: "https://jobs.lever.co/%s",
+		APIURLTemplate:     "https://api.lever.co/v0/postings/%s?mode=json",
+		SlugPattern:        regexp.MustCompile(`^[A-Za-z0-9._-]+$`),
+		MatchURL:           hostPathMatcher("jobs.lever.co"),
+		PagePatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)(?:jobs|api)\.lever\.co/(?:v0/postings/)?([a-z0-9._-]+)`),
+		},
+		NewScraper: func(atsURL string) Scraper { return NewLeverScraper(atsURL) },
+	})
+}
+
 type LeverScraper struct {
 	Url string
 }
@@ -21,10 +38,15 @@ type LeverJob struct {
 	ApplyURL      string            `json:"applyUrl"`
 	WorkplaceType string            `json:"workplaceType"`
 	SalaryRange   *LeverSalaryRange `json:"salaryRange,omitempty"`
+	// CreatedAt is in Unix milliseconds
+	CreatedAt int64 `json:"createdAt"`
 }
 
 type LeverCategories struct {
-	Location string `json:"location"`
+	Location   string `json:"location"`
+	Commitment string `json:"commitment"`
+	Team       string `json:"team"`
+	Department string `json:"department"`
 }
 
 type LeverSalaryRange struct {
@@ -34,6 +56,41 @@ type LeverSalaryRange struct {
 	Max      *float64 `json:"max"`
 }
 
+// formatLeverSalary formats a salary range as e.g. "EUR 80000 - 100000
+// per-year-salary", leaving out the parts Lever doesn't give
+func formatLeverSalary(salary *LeverSalaryRange) string {
+	if salary == nil {
+		return ""
+	}
+
+	var parts []string
+	if salary.Currency != "" {
+		parts = append(parts, salary.Currency)
+	}
+
+	var minStr, maxStr string
+	if salary.Min != nil {
+		minStr = fmt.Sprintf("%.0f", *salary.Min)
+	}
+	if salary.Max != nil {
+		maxStr = fmt.Sprintf("%.0f", *salary.Max)
+	}
+
+	if minStr != "" && maxStr != "" {
+		parts = append(parts, minStr+" - "+maxStr)
+	} else if minStr != "" {
+		parts = append(parts, minStr)
+	} else if maxStr != "" {
+		parts = append(parts, maxStr)
+	}
+
+	if salary.Interval != "" {
+		parts = append(parts, salary.Interval)
+	}
+
+	return strings.Join(parts, " ")
+}
+
 func NewLeverScraper(atsURL string) LeverScraper {
 	return LeverScraper{
 		Url: atsURL,
@@ -41,66 +98,52 @@ func NewLeverScraper(atsURL string) LeverScraper {
 }
 
 func (s LeverScraper) Scrape() ([]Job, error) {
+	jobs, _, err := s.ScrapeIfChanged(BoardState{})
+	return jobs, err
+}
+
+func (s LeverScraper) ScrapeIfChanged(state BoardState) ([]Job, BoardState, error) {
 	var leverResp LeverResponse
-	if err := FetchJSON(s.Url, &leverResp); err != nil {
-		return nil, err
+	state, err := FetchJSONIfChanged(s.Url, state, &leverResp)
+	if err != nil {
+		return nil, state, err
 	}
 
-	scrapeTime := time.Now().Format(time.RFC3339)
+	scrapeTime := time.Now().UTC().Format(time.RFC3339)
 	jobs := make([]Job, 0, len(leverResp))
 	for _, leverJob := range leverResp {
 		if leverJob.WorkplaceType != "remote" {
 			continue
 		}
 
-		salaryRange := ""
-		if leverJob.SalaryRange != nil {
-			parts := []string{}
-			if leverJob.SalaryRange.Currency != "" {
-				parts = append(parts, leverJob.SalaryRange.Currency)
-			}
-
-			var minStr, maxStr string
-			if leverJob.SalaryRange.Min != nil {
-				minStr = fmt.Sprintf("%.0f", *leverJob.SalaryRange.Min)
-			}
-			if leverJob.SalaryRange.Max != nil {
-				maxStr = fmt.Sprintf("%.0f", *leverJob.SalaryRange.Max)
-			}
-
-			if minStr != "" && maxStr != "" {
-				parts = append(parts, minStr+" - "+maxStr)
-			} else if minStr != "" {
-				parts = append(parts, minStr)
-			} else if maxStr != "" {
-				parts = append(parts, maxStr)
-			}
-
-			if leverJob.SalaryRange.Interval != "" {
-				parts = append(parts, leverJob.SalaryRange.Interval)
-			}
-
-			if len(parts) > 0 {
-				for i, part := range parts {
-					if i > 0 {
-						salaryRange += " "
-					}
-					salaryRange += part
-				}
-			}
+		// Postings without a creation date are dated when first scraped
+		publishedAt, publishedAtRaw := scrapeTime, ""
+		if leverJob.CreatedAt != 0 {
+			publishedAtRaw = strconv.FormatInt(leverJob.CreatedAt, 10)
+			publishedAt = NormalizePublishedAt(publishedAtRaw)
 		}
 
+		department := leverJob.Categories.Department
+		if department == "" {
+			department = leverJob.Categories.Team
+		}
 		job := Job{
-			Title:       leverJob.Text,
-			Url:         leverJob.HostedURL,
-			Description: leverJob.Description,
-			SalaryRange: salaryRange,
-			Location:    leverJob.Categories.Location,
-			PublishedAt: scrapeTime,
+			ExternalID:     leverJob.ID,
+			Title:          leverJob.Text,
+			Url:            leverJob.HostedURL,
+			ApplyURL:       leverJob.ApplyURL,
+			Description:    leverJob.Description,
+			SalaryRange:    formatLeverSalary(leverJob.SalaryRange),
+			Location:       leverJob.Categories.Location,
+			PublishedAt:    publishedAt,
+			PublishedAtRaw: publishedAtRaw,
+			EmploymentType: NormalizeEmploymentType(leverJob.Categories.Commitment),
+			Department:     department,
 		}
 		jobs = append(jobs, job)
 	}
 
+	jobs = normalizeJobs(jobs)
 	LogScrapeResult(s.Url, len(jobs))
-	return jobs, nil
+	return jobs, state, nil
 }
@@ -3,9 +3,25 @@ package scraping
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+func init() {
+	RegisterATS(ATS{
+		Type:               "recruitee",
+		CareersURLTemplate: "https://%s.recruitee.com",
+		APIURLTemplate:     "https://%s.recruitee.com/api/offers/",
+		// Recruitee boards are subdomains, so slugs must be valid DNS labels
+		SlugPattern: regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`),
+		MatchURL:    subdomainMatcher("recruitee.com"),
+		PagePatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)//([a-z0-9-]+)\.recruitee\.com`),
+		},
+		NewScraper: func(atsURL string) Scraper { return NewRecruiteeScraper(atsURL) },
+	})
+}
+
 type RecruiteeScraper struct {
 	Url string
 }
@@ -19,10 +35,16 @@ type RecruiteeOffer struct {
 	Title       string            `json:"title"`
 	Description string            `json:"description"`
 	CareersURL  string            `json:"careers_url"`
+	ApplyURL    string            `json:"careers_apply_url"`
 	Locations   RecruiteeLocation `json:"location,omitempty"`
-	CreatedAt   string            `json:"created_at"`
-	UpdatedAt   string            `json:"updated_at"`
-	Salary      RecruiteeSalary   `json:"salary,omitempty"`
+	// Remote is nil for boards which don't say
+	Remote     *bool           `json:"remote"`
+	CreatedAt  string          `json:"created_at"`
+	UpdatedAt  string          `json:"updated_at"`
+	Salary     RecruiteeSalary `json:"salary,omitempty"`
+	Department string          `json:"department"`
+	// EmploymentTypeCode is e.g. "fulltime_permanent" or "freelance"
+	EmploymentTypeCode string `json:"employment_type_code"`
 }
 
 type RecruiteeLocation struct {
@@ -100,28 +122,45 @@ func NewRecruiteeScraper(atsURL string) RecruiteeScraper {
 }
 
 func (s RecruiteeScraper) Scrape() ([]Job, error) {
+	jobs, _, err := s.ScrapeIfChanged(BoardState{})
+	return jobs, err
+}
+
+func (s RecruiteeScraper) ScrapeIfChanged(state BoardState) ([]Job, BoardState, error) {
 	var recruiteeResp RecruiteeResponse
-	if err := FetchJSON(s.Url, &recruiteeResp); err != nil {
-		return nil, err
+	state, err := FetchJSONIfChanged(s.Url, state, &recruiteeResp)
+	if err != nil {
+		return nil, state, err
 	}
 
 	jobs := make([]Job, 0, len(recruiteeResp.Offers))
 	for _, recruiteeOffer := range recruiteeResp.Offers {
+		if recruiteeOffer.Remote != nil && !*recruiteeOffer.Remote {
+			continue
+		}
+
 		publishedAt := recruiteeOffer.CreatedAt
 		if publishedAt == "" {
 			publishedAt = recruiteeOffer.UpdatedAt
 		}
 
 		job := Job{
-			Title:       recruiteeOffer.Title,
-			Url:         recruiteeOffer.CareersURL,
-			Description: recruiteeOffer.Description,
-			PublishedAt: publishedAt,
-			SalaryRange: string(recruiteeOffer.Salary),
+			ExternalID:     formatID(recruiteeOffer.ID),
+			Title:          recruiteeOffer.Title,
+			Url:            recruiteeOffer.CareersURL,
+			ApplyURL:       recruiteeOffer.ApplyURL,
+			Description:    recruiteeOffer.Description,
+			PublishedAt:    NormalizePublishedAt(publishedAt),
+			PublishedAtRaw: publishedAt,
+			SalaryRange:    string(recruiteeOffer.Salary),
+			Location:       recruiteeOffer.Locations.City,
+			EmploymentType: NormalizeEmploymentType(recruiteeOffer.EmploymentTypeCode),
+			Department:     recruiteeOffer.Department,
 		}
 		jobs = append(jobs, job)
 	}
 
+	jobs = normalizeJobs(jobs)
 	LogScrapeResult(s.Url, len(jobs))
-	return jobs, nil
+	return jobs, state, nil
 }
@@ -0,0 +1,106 @@
+package scraping
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ATS describes a supported applicant tracking system. Each adapter registers
+// one from an init function so that building scrapers, filling URLs from a
+// slug and detecting the ATS behind a careers page work for it automatically.
+type ATS struct {
+	Type string
+	// CareersURLTemplate and APIURLTemplate are fmt templates taking the board slug
+	CareersURLTemplate string
+	APIURLTemplate     string
+	// SlugPattern validates board slugs before they are put into URLs
+	SlugPattern *regexp.Regexp
+	// MatchURL extracts the board slug from a URL hosted by the ATS
+	MatchURL func(u *url.URL) (slug string, ok bool)
+	// PagePatterns find boards embedded in a careers page (links, iframes,
+	// scripts). The first submatch is the slug.
+	PagePatterns []*regexp.Regexp
+	NewScraper   func(atsURL string) Scraper
+}
+
+var (
+	registry      = map[string]ATS{}
+	registryOrder []string
+)
+
+// RegisterATS makes an ATS available by its type. It panics if the type is
+// registered twice.
+func RegisterATS(ats ATS) {
+	if _, ok := registry[ats.Type]; ok {
+		panic(fmt.Sprintf("ATS %q registered twice", ats.Type))
+	}
+	registry[ats.Type] = ats
+	registryOrder = append(registryOrder, ats.Type)
+}
+
+// LookupATS returns the registered ATS with the given type
+func LookupATS(atsType string) (ATS, bool) {
+	ats, ok := registry[atsType]
+	return ats, ok
+}
+
+// RegisteredATS returns every registered ATS in registration order
+func RegisteredATS() []ATS {
+	all := make([]ATS, 0, len(registryOrder))
+	for _, atsType := range registryOrder {
+		all = append(all, registry[atsType])
+	}
+	return all
+}
+
+// ATSTypes returns the types of every registered ATS in registration order
+func ATSTypes() []string {
+	return append([]string(nil), registryOrder...)
+}
+
+// ValidateSlug checks that slug can name a board on the ATS
+func (a ATS) ValidateSlug(slug string) error {
+	if slug == "" {
+		return fmt.Errorf("%s slug is required", a.Type)
+	}
+	if a.SlugPattern != nil && !a.SlugPattern.MatchString(slug) {
+		return fmt.Errorf("invalid %s slug %q", a.Type, slug)
+	}
+	return nil
+}
+
+func (a ATS) CareersURL(slug string) string {
+	return fmt.Sprintf(a.CareersURLTemplate, slug)
+}
+
+func (a ATS) APIURL(slug string) string {
+	return fmt.Sprintf(a.APIURLTemplate, slug)
+}
+
+// hostPathMatcher returns a MatchURL for ATS that host boards at https://<host>/<slug>
+func hostPathMatcher(hosts ...string) func(u *url.URL) (string, bool) {
+	return func(u *url.URL) (string, bool) {
+		host := strings.ToLower(strings.TrimPrefix(u.Hostname(), "www."))
+		for _, h := range hosts {
+			if host == h {
+				slug := firstPathSegment(u.Path)
+				return slug, slug != ""
+			}
+		}
+		return "", false
+	}
+}
+
+// subdomainMatcher returns a MatchURL for ATS that host boards at https://<slug>.<domain>
+func subdomainMatcher(domain string) func(u *url.URL) (string, bool) {
+	return func(u *url.URL) (string, bool) {
+		host := strings.ToLower(u.Hostname())
+		if !strings.HasSuffix(host, "."+domain) {
+			return "", false
+		}
+		slug := strings.TrimSuffix(host, "."+domain)
+		return slug, !strings.Contains(slug, ".")
+	}
+}
@@ -0,0 +1,161 @@
+package scraping
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	record = flag.Bool("record", false, "fetch the fixtures of live boards from their ATS and rewrite their golden files")
+	update = flag.Bool("update", false, "rewrite the golden files with the jobs scraped from the fixtures")
+)
+
+// scraperFixtures are the ATS responses in testdata/<ats>/<name>.json the
+// scrapers are run against, each with its expected jobs in
+// testdata/<ats>/<name>.golden.json
+var scraperFixtures = []struct {
+	ats  string
+	name string
+	// board is the live board -record fetches the fixture from, any public
+	// board of the ATS will do. Fixtures without one are written by hand to
+	// cover edge cases.
+	board string
+}{
+	{ats: "ashby", name: "board", board: "ashby"},
+	{ats: "greenhouse", name: "board", board: "gitlab"},
+	{ats: "lever", name: "board", board: "palantir"},
+	{ats: "recruitee", name: "board", board: "tellent"},
+	// location as an object and salary as a string
+	{ats: "recruitee", name: "location_object"},
+	// salary objects missing fields
+	{ats: "recruitee", name: "salary_partial"},
+}
+
+func TestScrapersAgainstFixtures(t *testing.T) {
+	for _, fixture := range scraperFixtures {
+		t.Run(fixture.ats+"/"+fixture.name, func(t *testing.T) {
+			ats, ok := LookupATS(fixture.ats)
+			if !ok {
+				t.Fatalf("ATS %q is not registered", fixture.ats)
+			}
+			path := filepath.Join("testdata", fixture.ats, fixture.name+".json")
+			recorded := *record && fixture.board != ""
+			if recorded {
+				recordFixture(t, ats.APIURL(fixture.board), path)
+			}
+
+			jobs, err := ats.NewScraper(replayServer(t, path)).Scrape()
+			if err != nil {
+				t.Fatalf("scraping: %v", err)
+			}
+			assertGolden(t, filepath.Join("testdata", fixture.ats, fixture.name+".golden.json"), jobs, recorded)
+		})
+	}
+}
+
+// replayServer serves the fixture at path whatever the request, returning
+// the URL to scrape
+func replayServer(t *testing.T, path string) string {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return serveResponse(t, http.StatusOK, body)
+}
+
+// serveResponse starts a server answering every request with status and
+// body, returning its URL
+func serveResponse(t *testing.T, status int, body []byte) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// recordFixture saves the live response of url as the fixture at path
+func recordFixture(t *testing.T, url, path string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("recording %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if err := ValidateResponse(resp); err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("recording %s: %v", url, err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		t.Fatalf("recording %s: %v", url, err)
+	}
+	indented.WriteByte('\n')
+	if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+}
+
+// assertGolden compares jobs with the golden file at path, rewriting it
+// instead with -update or when rewrite is set
+func assertGolden(t *testing.T, path string, jobs []Job, rewrite bool) {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(jobs); err != nil {
+		t.Fatalf("encoding jobs: %v", err)
+	}
+	got := buf.Bytes()
+
+	if *update || rewrite {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file, run with -update to create it: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("jobs differ from %s, run with -update if the change is expected\n%s", path, lineDiff(want, got))
+	}
+}
+
+// lineDiff lists the lines of want and got which differ, side by side
+func lineDiff(want, got []byte) string {
+	wantLines := bytes.Split(want, []byte("\n"))
+	gotLines := bytes.Split(got, []byte("\n"))
+	var diff bytes.Buffer
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g []byte
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if !bytes.Equal(w, g) {
+			fmt.Fprintf(&diff, "line %d:\n  want %s\n  got  %s\n", i+1, w, g)
+		}
+	}
+	return diff.String()
+}
@@ -0,0 +1,136 @@
+package scraping
+
+import "regexp"
+
+// Signals found in job descriptions, stored on jobs as flags
+const (
+	SignalLocationRestricted = "location-restricted"
+	SignalWorkAuthorization  = "work-authorization"
+	SignalVisaSponsorship    = "visa-sponsorship"
+	SignalRelocation         = "relocation"
+	SignalEmployerOfRecord   = "employer-of-record"
+	SignalContractorOnly     = "contractor-only"
+)
+
+// Signal is something a job description says about who can take the job
+type Signal struct {
+	Name  string
+	Label string
+	// Restriction is true for signals limiting where candidates can live or
+	// work from, which "remote" listings often only mention in the description
+	Restriction bool
+
+	patterns *regexp.Regexp
+	// unless cancels patterns, e.g. "we cannot sponsor visas"
+	unless *regexp.Regexp
+}
+
+// noSponsorship matches descriptions ruling out visa sponsorship
+var noSponsorship = []string{
+	`(not|unable to|cannot|can't|won't|will not|don't|do not|are not able to) (currently )?(be able to )?(provide |offer |support )?(visa |employment |work permit )?sponsor\w*`,
+	`without (the )?(need for |requiring )?(current or future )?(visa |employment )?sponsorship`,
+	`no (visa )?sponsorship`,
+	`sponsorship (is )?not (available|provided|offered|possible)`,
+}
+
+// Signals lists the signals ExtractSignals looks for, in display order
+var Signals = []Signal{
+	{
+		Name:        SignalLocationRestricted,
+		Label:       "Location restricted",
+		Restriction: true,
+		patterns: mustCompileWords(
+			`must (currently )?(be )?(reside|residing|located|based|live|living) (with)?in`,
+			`(candidates|applicants|you) (must|need to|should) (be )?(currently )?(reside|residing|located|based|live|living) (with)?in`,
+			`(only|exclusively) (open to|accepting|considering|hiring) (applications from )?(candidates|applicants|people|residents)( who are)?( currently)? (located |based |residing |living )?in`,
+			`(can|are able to) only (hire|employ) (in|people|candidates|applicants|residents)`,
+			`(us|u\.s\.|usa|united states|uk|canada|eu|emea|europe)[- ]only`,
+			`(located|based|residing) in (one of )?the following (states|countries|locations)`,
+			`(not|unable to) (able to )?(hire|employ) (in|outside|candidates outside)`,
+		),
+	},
+	{
+		Name:        SignalWorkAuthorization,
+		Label:       "Work authorization required",
+		Restriction: true,
+		patterns: mustCompileWords(append([]string{
+			`(must|should|need to) (be )?(legally )?(authorized|authorised|eligible|permitted) to work in`,
+			`(legal )?(right|authorization|authorisation|eligibility|permission) to work in`,
+			`(valid )?work (permit|visa) (is )?required`,
+		}, noSponsorship...)...),
+	},
+	{
+		Name:  SignalVisaSponsorship,
+		Label: "Visa sponsorship",
+		patterns: mustCompileWords(
+			`(we|will|can|happy to|able to|glad to) (\w+ )?sponsor\w*`,
+			`visa sponsorship (is )?(available|provided|offered|possible)`,
+			`sponsor(ing)? (your |a |work )?(visas?|work permits?)`,
+		),
+		unless: mustCompileWords(noSponsorship...),
+	},
+	{
+		Name:  SignalRelocation,
+		Label: "Relocation support",
+		patterns: mustCompileWords(
+			`relocation (assistance|package|support|bonus|budget|stipend|allowance|help)`,
+			`(help|support|assist) (you )?(with )?(your )?relocat\w*`,
+		),
+		unless: mustCompileWords(`no relocation`, `relocation (is )?not (available|provided|offered)`),
+	},
+	{
+		Name:  SignalEmployerOfRecord,
+		Label: "Hired through an employer of record",
+		patterns: mustCompileWords(
+			`employers? of record`,
+			`eor`,
+			`(through|via|using) (deel|remote\.com|oyster|papaya( global)?|rippling|velocity global|omnipresent|globalization partners|multiplier)`,
+		),
+	},
+	{
+		Name:  SignalContractorOnly,
+		Label: "Contractor only",
+		patterns: mustCompileWords(
+			`contract(or)?[- ]only`,
+			`(independent|freelance|b2b) contract(or|ors)?`,
+			`on a (contract|contractor|freelance|b2b) basis`,
+			`(this is|this will be) a (contract|contractor|freelance) (role|position|engagement)`,
+			`1099`,
+		),
+	},
+}
+
+func mustCompileWords(patterns ...string) *regexp.Regexp {
+	re, err := compileWords(patterns, true)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// LookupSignal returns the signal with the given name
+func LookupSignal(name string) (Signal, bool) {
+	for _, signal := range Signals {
+		if signal.Name == name {
+			return signal, true
+		}
+	}
+	return Signal{}, false
+}
+
+// ExtractSignals returns the names of the signals the title or description
+// of job mentions, in the order of Signals
+func ExtractSignals(job Job) []string {
+	text := job.Title + "\n" + DescriptionText(job.Description)
+	var names []string
+	for _, signal := range Signals {
+		if !signal.patterns.MatchString(text) {
+			continue
+		}
+		if signal.unless != nil && signal.unless.MatchString(text) {
+			continue
+		}
+		names = append(names, signal.Name)
+	}
+	return names
+}
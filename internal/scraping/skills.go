@@ -0,0 +1,124 @@
+package scraping
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"go.yaml.in/yaml/v3"
+)
+
+//go:embed skills.yml
+var skillsDictionary []byte
+
+// skills are the embedded skill tags, in dictionary order
+var skills = mustParseSkills(skillsDictionary)
+
+// Skill is a technology or skill jobs are tagged with
+type Skill struct {
+	// Tag is the slug used in URLs, e.g. "go"
+	Tag string
+	// Name is how the tag is shown, e.g. "Go"
+	Name string
+
+	synonyms      *regexp.Regexp
+	caseSensitive *regexp.Regexp
+	title         *regexp.Regexp
+	exclude       *regexp.Regexp
+}
+
+// ParseSkills parses a skill dictionary in the format of the embedded
+// skills.yml
+func ParseSkills(data []byte) ([]Skill, error) {
+	var entries []struct {
+		Tag           string   `yaml:"tag"`
+		Name          string   `yaml:"name"`
+		Synonyms      []string `yaml:"synonyms"`
+		CaseSensitive []string `yaml:"caseSensitive"`
+		Title         []string `yaml:"title"`
+		Exclude       []string `yaml:"exclude"`
+	}
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing skills: %w", err)
+	}
+
+	seen := map[string]bool{}
+	parsed := make([]Skill, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Tag == "" || entry.Name == "" {
+			return nil, fmt.Errorf("skill %q: tag and name are required", entry.Tag)
+		}
+		if seen[entry.Tag] {
+			return nil, fmt.Errorf("skill %q is defined twice", entry.Tag)
+		}
+		seen[entry.Tag] = true
+
+		skill := Skill{Tag: entry.Tag, Name: entry.Name}
+		var err error
+		if skill.synonyms, err = compileWords(entry.Synonyms, true); err != nil {
+			return nil, fmt.Errorf("skill %s: %w", entry.Tag, err)
+		}
+		if skill.caseSensitive, err = compileWords(entry.CaseSensitive, false); err != nil {
+			return nil, fmt.Errorf("skill %s: %w", entry.Tag, err)
+		}
+		if skill.title, err = compileWords(entry.Title, true); err != nil {
+			return nil, fmt.Errorf("skill %s: %w", entry.Tag, err)
+		}
+		if skill.exclude, err = compileWords(entry.Exclude, true); err != nil {
+			return nil, fmt.Errorf("skill %s: %w", entry.Tag, err)
+		}
+		parsed = append(parsed, skill)
+	}
+	return parsed, nil
+}
+
+func mustParseSkills(data []byte) []Skill {
+	parsed, err := ParseSkills(data)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// Skills returns the embedded skill tags in dictionary order
+func Skills() []Skill {
+	return skills
+}
+
+// LookupSkill returns the embedded skill with the given tag
+func LookupSkill(tag string) (Skill, bool) {
+	for _, skill := range skills {
+		if skill.Tag == tag {
+			return skill, true
+		}
+	}
+	return Skill{}, false
+}
+
+// matches reports whether the skill is mentioned in the title or the
+// description text of a job
+func (s Skill) matches(title, description string) bool {
+	if s.exclude != nil {
+		title = s.exclude.ReplaceAllString(title, " ")
+		description = s.exclude.ReplaceAllString(description, " ")
+	}
+	for _, pattern := range []*regexp.Regexp{s.synonyms, s.caseSensitive} {
+		if pattern != nil && (pattern.MatchString(title) || pattern.MatchString(description)) {
+			return true
+		}
+	}
+	return s.title != nil && s.title.MatchString(title)
+}
+
+// ExtractSkills returns the tags of the embedded skills mentioned by job, in
+// dictionary order
+func ExtractSkills(job Job) []string {
+	description := DescriptionText(job.Description)
+	var tags []string
+	for _, skill := range skills {
+		if skill.matches(job.Title, description) {
+			tags = append(tags, skill.Tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,201 @@
+package scraping
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WorkdayMinutes is the length of the working day timezone overlaps are
+// computed for, 9 to 5 local time
+const WorkdayMinutes = 8 * 60
+
+// TimezoneWindow is the range of UTC offsets, in minutes east of UTC, a job
+// expects candidates to work from. A job requiring overlap with a timezone
+// has that timezone as its window.
+type TimezoneWindow struct {
+	Min int
+	Max int
+}
+
+// Overlap returns the minutes of working day shared by someone working at
+// offset and the closest offset of the window. Offsets are compared on a
+// line, so the few hours shared across the date line, e.g. by New Zealand
+// and California, are not counted.
+func (w TimezoneWindow) Overlap(offset int) int {
+	distance := 0
+	if offset < w.Min {
+		distance = w.Min - offset
+	} else if offset > w.Max {
+		distance = offset - w.Max
+	}
+	return max(WorkdayMinutes-distance, 0)
+}
+
+func (w TimezoneWindow) String() string {
+	if w.Min == w.Max {
+		return FormatUTCOffset(w.Min)
+	}
+	return FormatUTCOffset(w.Min) + " to " + FormatUTCOffset(w.Max)
+}
+
+// FormatUTCOffset formats an offset in minutes as e.g. "UTC+5:30" or "UTC−5"
+func FormatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "−", -offset
+	}
+	if offset%60 != 0 {
+		return fmt.Sprintf("UTC%s%d:%02d", sign, offset/60, offset%60)
+	}
+	return fmt.Sprintf("UTC%s%d", sign, offset/60)
+}
+
+// timezoneOffsets are the standard offsets of common timezone names.
+// Ambiguous ones take their most common meaning in job postings: IST is
+// India and CST is US Central.
+var timezoneOffsets = map[string]int{
+	"utc": 0, "gmt": 0, "wet": 0, "bst": 60, "cet": 60, "cest": 120, "eet": 120, "eest": 180,
+	"msk": 180, "gst": 240, "ist": 330, "sgt": 480, "hkt": 480, "awst": 480, "jst": 540,
+	"kst": 540, "aest": 600, "aedt": 660, "nzst": 720, "nzdt": 780,
+	"pt": -480, "pst": -480, "pdt": -420, "pacific": -480, "mt": -420, "mst": -420, "mdt": -360,
+	"mountain": -420, "ct": -360, "cst": -360, "cdt": -300, "central": -360, "et": -300,
+	"est": -300, "edt": -240, "eastern": -300, "ast": -240, "brt": -180, "art": -180,
+}
+
+// regionWindows map the regions found in locations and descriptions to the
+// offsets they span
+var regionWindows = []struct {
+	pattern *regexp.Regexp
+	window  TimezoneWindow
+}{
+	{mustCompileWords(`us`, `usa`, `u\.s\.?`, `united states`, `north america`, `us[- ]based`), TimezoneWindow{-480, -300}},
+	{mustCompileWords(`canada`, `toronto`, `vancouver`, `montreal`), TimezoneWindow{-480, -210}},
+	{mustCompileWords(`americas`), TimezoneWindow{-480, -180}},
+	{mustCompileWords(`latam`, `latin america`, `south america`, `mexico`, `colombia`, `argentina`, `chile`, `peru`), TimezoneWindow{-360, -180}},
+	{mustCompileWords(`brazil`, `brasil`), TimezoneWindow{-180, -180}},
+	{mustCompileWords(`new york`, `boston`, `miami`, `atlanta`), TimezoneWindow{-300, -300}},
+	{mustCompileWords(`san francisco`, `los angeles`, `seattle`, `bay area`), TimezoneWindow{-480, -480}},
+	{mustCompileWords(`uk`, `united kingdom`, `england`, `scotland`, `london`, `ireland`, `dublin`, `portugal`, `lisbon`), TimezoneWindow{0, 0}},
+	{mustCompileWords(`europe`, `european`, `eu`), TimezoneWindow{0, 120}},
+	{mustCompileWords(`germany`, `berlin`, `munich`, `france`, `paris`, `spain`, `madrid`, `barcelona`, `netherlands`, `amsterdam`, `belgium`, `italy`, `switzerland`, `austria`, `poland`, `sweden`, `denmark`, `norway`, `czechia`, `czech republic`), TimezoneWindow{60, 60}},
+	{mustCompileWords(`finland`, `greece`, `romania`, `bulgaria`, `ukraine`, `estonia`, `latvia`, `lithuania`), TimezoneWindow{120, 120}},
+	{mustCompileWords(`emea`), TimezoneWindow{0, 240}},
+	{mustCompileWords(`africa`, `nigeria`, `kenya`, `south africa`, `egypt`), TimezoneWindow{0, 180}},
+	{mustCompileWords(`middle east`, `uae`, `dubai`, `israel`, `turkey`), TimezoneWindow{120, 240}},
+	{mustCompileWords(`india`, `bangalore`, `bengaluru`), TimezoneWindow{330, 330}},
+	{mustCompileWords(`apac`, `asia`, `asia pacific`), TimezoneWindow{330, 600}},
+	{mustCompileWords(`singapore`, `philippines`, `china`, `hong kong`, `taiwan`), TimezoneWindow{480, 480}},
+	{mustCompileWords(`japan`, `tokyo`, `korea`), TimezoneWindow{540, 540}},
+	{mustCompileWords(`australia`, `sydney`, `melbourne`), TimezoneWindow{480, 600}},
+	{mustCompileWords(`new zealand`), TimezoneWindow{720, 720}},
+}
+
+// worldwide locations accept candidates anywhere, so they have no window
+var worldwide = mustCompileWords(`worldwide`, `anywhere`, `global`, `globally`, `world`)
+
+var (
+	timezoneAbbreviations = `(utc|gmt|wet|bst|cet|cest|eet|eest|msk|gst|ist|sgt|hkt|awst|jst|kst|aest|aedt|nzst|nzdt|pt|pst|pdt|mt|mst|mdt|ct|cst|cdt|et|est|edt|ast|brt|art)`
+	timezoneNames         = `(utc|gmt|wet|bst|cet|cest|eet|eest|msk|gst|ist|sgt|hkt|awst|jst|kst|aest|aedt|nzst|nzdt|pt|pst|pdt|pacific|mt|mst|mdt|mountain|ct|cst|cdt|central|et|est|edt|eastern|ast|brt|art)`
+	utcOffset             = `(?:utc|gmt) ?([-+−]) ?(\d{1,2})(?::?(\d{2}))?`
+
+	// "CET ± 3 hours", "CET +/- 2h", "UTC+1 +/-3 hours"
+	plusMinusPattern = regexp.MustCompile(`(?i)\b(?:` + timezoneNames + `|` + utcOffset + `) ?(?:±|\+/-|\+-|\+ ?/ ?-|plus or minus) ?(\d{1,2}) ?h`)
+	// "between UTC-3 and UTC+3", "UTC-5 to UTC+1"
+	offsetRangePattern = regexp.MustCompile(`(?i)\b` + utcOffset + ` ?(?:to|and|-|–|—) ?` + utcOffset)
+	// "UTC+2", "GMT-5"
+	singleOffsetPattern = regexp.MustCompile(`(?i)\b` + utcOffset)
+	// "overlap with PST", "CET working hours", "Eastern time"
+	timezoneHoursPattern = regexp.MustCompile(`(?i)(?:overlap(?:ping)? (?:with|in)|aligned (?:with|to)) (?:the )?` + timezoneAbbreviations + `\b|\b` + timezoneNames + ` (?:business |working |office |core )?(?:hours|time(?: ?zones?)?)\b`)
+	// "European time zones", "in a US timezone"
+	regionTimezonePattern = regexp.MustCompile(`(?i)((?:\w+ ){0,4})time ?zones?`)
+)
+
+// ParseTimezoneWindow returns the window of UTC offsets job expects, from an
+// explicit requirement in its description, or else from its location, or
+// nil when it has neither or accepts candidates anywhere
+func ParseTimezoneWindow(job Job) *TimezoneWindow {
+	if window := parseTimezoneRequirement(DescriptionText(job.Description)); window != nil {
+		return window
+	}
+	if worldwide.MatchString(job.Location) {
+		return nil
+	}
+	return regionWindow(job.Location)
+}
+
+// parseTimezoneRequirement finds the first explicit timezone requirement of
+// a description, trying the most precise forms first
+func parseTimezoneRequirement(text string) *TimezoneWindow {
+	if m := plusMinusPattern.FindStringSubmatch(text); m != nil {
+		center, ok := namedOrUTCOffset(m[1], m[2], m[3], m[4])
+		hours, err := strconv.Atoi(m[5])
+		if ok && err == nil {
+			return &TimezoneWindow{center - hours*60, center + hours*60}
+		}
+	}
+	if m := offsetRangePattern.FindStringSubmatch(text); m != nil {
+		from, _ := namedOrUTCOffset("", m[1], m[2], m[3])
+		to, _ := namedOrUTCOffset("", m[4], m[5], m[6])
+		return &TimezoneWindow{min(from, to), max(from, to)}
+	}
+	if m := singleOffsetPattern.FindStringSubmatch(text); m != nil {
+		offset, _ := namedOrUTCOffset("", m[1], m[2], m[3])
+		return &TimezoneWindow{offset, offset}
+	}
+	if m := timezoneHoursPattern.FindStringSubmatch(text); m != nil {
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+		offset := timezoneOffsets[strings.ToLower(name)]
+		return &TimezoneWindow{offset, offset}
+	}
+	for _, m := range regionTimezonePattern.FindAllStringSubmatch(text, -1) {
+		if window := regionWindow(m[1]); window != nil {
+			return window
+		}
+	}
+	return nil
+}
+
+// namedOrUTCOffset returns the offset of a timezone name, or of the sign,
+// hours and minutes of a UTC offset
+func namedOrUTCOffset(name, sign, hours, minutes string) (int, bool) {
+	if name != "" {
+		offset, ok := timezoneOffsets[strings.ToLower(name)]
+		return offset, ok
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, false
+	}
+	offset := h * 60
+	if minutes != "" {
+		m, _ := strconv.Atoi(minutes)
+		offset += m
+	}
+	if sign == "-" || sign == "−" {
+		offset = -offset
+	}
+	return offset, true
+}
+
+// regionWindow returns the window spanning every region text mentions
+func regionWindow(text string) *TimezoneWindow {
+	var window *TimezoneWindow
+	for _, region := range regionWindows {
+		if !region.pattern.MatchString(text) {
+			continue
+		}
+		if window == nil {
+			w := region.window
+			window = &w
+			continue
+		}
+		window.Min = min(window.Min, region.window.Min)
+		window.Max = max(window.Max, region.window.Max)
+	}
+	return window
+}
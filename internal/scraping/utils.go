@@ -1,28 +1,20 @@
 package scraping
 
 import (
-	"encoding/json"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
-func FetchJSON(url string, target interface{}) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return fmt.Errorf("getting %v: %w", url, err)
-	}
-	defer resp.Body.Close()
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
 
-	if err := ValidateResponse(resp); err != nil {
-		return err
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
-		return fmt.Errorf("decoding JSON response: %w", err)
-	}
-
-	return nil
+func FetchJSON(url string, target interface{}) error {
+	_, err := FetchJSONIfChanged(url, BoardState{}, target)
+	return err
 }
 
 func ValidateResponse(resp *http.Response) error {
@@ -32,6 +24,37 @@ func ValidateResponse(resp *http.Response) error {
 	return nil
 }
 
+// normalizeJobs unescapes and trims the titles of scraped jobs and drops
+// the ones missing a title or URL, which can't be listed
+func normalizeJobs(jobs []Job) []Job {
+	kept := jobs[:0]
+	for _, job := range jobs {
+		job.Title = strings.TrimSpace(html.UnescapeString(job.Title))
+		if job.Title == "" || job.Url == "" {
+			continue
+		}
+		kept = append(kept, job)
+	}
+	return kept
+}
+
 func LogScrapeResult(sourceURL string, jobCount int) {
 	log.Printf("scraped %v jobs from %v", jobCount, sourceURL)
 }
+
+// formatID formats a numeric ATS job ID, or returns "" when the API left it out
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// DescriptionText strips the HTML of a job description, which Greenhouse
+// sends entity-escaped, leaving its text
+func DescriptionText(description string) string {
+	if !strings.Contains(description, "<") && strings.Contains(description, "&lt;") {
+		description = html.UnescapeString(description)
+	}
+	return html.UnescapeString(tagPattern.ReplaceAllString(description, " "))
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout sorts lexically in chronological order
+const backupTimeLayout = "20060102T150405Z"
+
+// Backup writes a consistent snapshot of the database to dir with VACUUM
+// INTO, which runs while the server and scraper keep using the database,
+// then deletes all but the keep most recent snapshots. It returns the path
+// of the new snapshot.
+func (db *DB) Backup(dir, prefix string, keep int) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	path := filepath.Join(dir, prefix+"-"+time.Now().UTC().Format(backupTimeLayout)+".sqlite")
+	if _, err := db.Exec(`VACUUM INTO $1`, path); err != nil {
+		return "", fmt.Errorf("writing backup: %w", err)
+	}
+
+	if err := rotateBackups(dir, prefix, keep); err != nil {
+		return path, err
+	}
+	return path, nil
+}
+
+// rotateBackups deletes the oldest snapshots of dir beyond keep
+func rotateBackups(dir, prefix string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	snapshots, err := filepath.Glob(filepath.Join(dir, prefix+"-*.sqlite"))
+	if err != nil {
+		return fmt.Errorf("listing backups: %w", err)
+	}
+	sort.Strings(snapshots)
+
+	for len(snapshots) > keep {
+		if err := os.Remove(snapshots[0]); err != nil {
+			return fmt.Errorf("deleting old backup: %w", err)
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// BackupPrefix derives the snapshot file prefix from the database path,
+// e.g. db for ./data/db.sqlite
+func BackupPrefix(dbPath string) string {
+	base := filepath.Base(dbPath)
+	if i := strings.IndexByte(base, '?'); i >= 0 {
+		base = base[:i]
+	}
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
@@ -3,29 +3,103 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strings"
 
 	_ "modernc.org/sqlite"
+
+	"github.com/ddahon/workfromearth/internal/config"
 )
 
 type DB struct {
 	*sql.DB
+	// reader is a pool of query-only connections, so pages keep loading
+	// while the scraper holds the write lock
+	reader *sql.DB
 }
 
-func NewDB(dbPath string) (*DB, error) {
-	db, err := sql.Open("sqlite", dbPath)
+var (
+	journalModes = map[string]bool{"wal": true, "delete": true, "truncate": true, "persist": true, "memory": true, "off": true}
+	syncLevels   = map[string]bool{"off": true, "normal": true, "full": true, "extra": true}
+)
+
+func NewDB(dbPath string, cfg config.SQLiteConfig) (*DB, error) {
+	journalMode := strings.ToLower(cfg.JournalMode)
+	if !journalModes[journalMode] {
+		return nil, fmt.Errorf("unknown sqlite.journalMode %q", cfg.JournalMode)
+	}
+	synchronous := strings.ToLower(cfg.Synchronous)
+	if !syncLevels[synchronous] {
+		return nil, fmt.Errorf("unknown sqlite.synchronous %q", cfg.Synchronous)
+	}
+
+	// Pragmas in the DSN apply to every connection of the pool, unlike a
+	// one-off PRAGMA statement which only reaches a single connection
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	pragmas.Add("_pragma", "journal_mode("+journalMode+")")
+	pragmas.Add("_pragma", "synchronous("+synchronous+")")
+
+	// Write transactions take the lock when they begin, so they wait for
+	// busy_timeout instead of failing with "database is locked" on upgrade
+	writeOptions := url.Values{"_txlock": {"immediate"}}
+	db, err := openPool(dbPath, pragmas, writeOptions, cfg.MaxOpenConns)
+	if err != nil {
+		return nil, err
+	}
+
+	// An in-memory database is private to its connection, so it cannot be
+	// shared with a second pool
+	if dbPath == ":memory:" || strings.Contains(dbPath, "mode=memory") {
+		return &DB{DB: db, reader: db}, nil
+	}
+
+	readOptions := url.Values{"_pragma": {"query_only(1)"}}
+	reader, err := openPool(dbPath, pragmas, readOptions, cfg.MaxReadConns)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &DB{DB: db, reader: reader}, nil
+}
+
+func openPool(dbPath string, pragmas, options url.Values, maxOpenConns int) (*sql.DB, error) {
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	params := pragmas.Encode()
+	if len(options) > 0 {
+		params += "&" + options.Encode()
+	}
+
+	db, err := sql.Open("sqlite", dbPath+separator+params)
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
+	if maxOpenConns > 0 {
+		db.SetMaxOpenConns(maxOpenConns)
+		db.SetMaxIdleConns(maxOpenConns)
+	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	_, _ = db.Exec("PRAGMA foreign_keys = ON")
+	return db, nil
+}
 
-	return &DB{db}, nil
+// Store returns the SQLite Repository of this database
+func (db *DB) Store() Store {
+	return NewRepository(db)
 }
 
 func (db *DB) Close() error {
+	if db.reader != db.DB {
+		db.reader.Close()
+	}
 	return db.DB.Close()
 }
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+)
+
+// JobFilter narrows the jobs listed on the index page. Its zero value
+// matches every job.
+type JobFilter struct {
+	// Query matches job titles, each backend searching them its own way
+	Query string
+	// EmploymentTypes keeps jobs with any of these employment types
+	EmploymentTypes []string
+	Department      string
+	Seniority       string
+	Category        string
+	// Tag keeps jobs tagged with this skill
+	Tag string
+	// Languages keeps jobs posted in any of these languages, and the ones too
+	// short to tell, which are only hidden once their language is known
+	Languages []string
+	// HideRestricted leaves out jobs whose description restricts where
+	// candidates can live or work from
+	HideRestricted bool
+	// UTCOffset, in minutes east of UTC, keeps jobs whose timezone window
+	// shares at least OverlapHours of working day with it, and the ones
+	// without a window
+	UTCOffset    *int
+	OverlapHours int
+}
+
+// Conditions returns the SQL conditions of the filter shared by both
+// backends, every field but Query, with their arguments appended to args.
+// Placeholders are numbered after the arguments already in args.
+func (f JobFilter) Conditions(args []any) ([]string, []any) {
+	var conditions []string
+	placeholder := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	in := func(values []string) string {
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			placeholders[i] = placeholder(value)
+		}
+		return "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if len(f.EmploymentTypes) > 0 {
+		conditions = append(conditions, "j.employment_type IN "+in(f.EmploymentTypes))
+	}
+	if f.Department != "" {
+		conditions = append(conditions, "j.department = "+placeholder(f.Department))
+	}
+	if f.Seniority != "" {
+		conditions = append(conditions, "j.seniority = "+placeholder(f.Seniority))
+	}
+	if f.Category != "" {
+		conditions = append(conditions, "j.category = "+placeholder(f.Category))
+	}
+	if f.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM job_tags t WHERE t.job_id = j.id AND t.tag = "+placeholder(f.Tag)+")")
+	}
+	if len(f.Languages) > 0 {
+		conditions = append(conditions, "(j.language IN "+in(f.Languages)+" OR j.language IS NULL)")
+	}
+	if f.HideRestricted {
+		for _, signal := range scraping.Signals {
+			if signal.Restriction {
+				conditions = append(conditions, "COALESCE(j.signals, '') NOT LIKE "+placeholder("%"+signal.Name+"%"))
+			}
+		}
+	}
+	if f.UTCOffset != nil {
+		// Overlap is at least OverlapHours while the offset is less than the
+		// rest of the working day away from the window
+		slack := scraping.WorkdayMinutes - f.OverlapHours*60
+		conditions = append(conditions, fmt.Sprintf("(j.tz_min_offset IS NULL OR %s BETWEEN j.tz_min_offset - %s AND j.tz_max_offset + %s)",
+			placeholder(*f.UTCOffset), placeholder(slack), placeholder(slack)))
+	}
+
+	return conditions, args
+}
+
+// TimezoneBounds returns the tz_min_offset and tz_max_offset columns of a
+// timezone window, NULL when the job has none
+func TimezoneBounds(window *scraping.TimezoneWindow) (sql.NullInt64, sql.NullInt64) {
+	if window == nil {
+		return sql.NullInt64{}, sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(window.Min), Valid: true}, sql.NullInt64{Int64: int64(window.Max), Valid: true}
+}
@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ddahon/workfromearth/database"
+)
+
+// ErrUntrackedDatabase is returned when the database has tables but no
+// schema_migrations table, meaning it was migrated by the old migrate.sh
+var ErrUntrackedDatabase = errors.New("database schema exists but applied migrations are not tracked, run `wfe migrate -baseline <last applied version>` first")
+
+// Migration is one schema change, read from NNNN_name.sql with its optional
+// NNNN_name.down.sql counterpart
+type Migration struct {
+	Version  string
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// AppliedMigration is a row of the schema_migrations table
+type AppliedMigration struct {
+	Version   string
+	Name      string
+	Checksum  string
+	AppliedAt string
+}
+
+// LoadMigrations reads the SQLite migrations embedded in the binary, sorted by version
+func LoadMigrations() ([]Migration, error) {
+	return ReadMigrations(database.Migrations, "migrations")
+}
+
+// Migrations returns the SQLite migrations embedded in the binary
+func (db *DB) Migrations() ([]Migration, error) {
+	return LoadMigrations()
+}
+
+// ReadMigrations reads the NNNN_name.sql and NNNN_name.down.sql files of dir,
+// sorted by version
+func ReadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	byVersion := map[string]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, ".sql") {
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, ".sql")
+		down := strings.HasSuffix(base, ".down")
+		base = strings.TrimSuffix(base, ".down")
+
+		version, name, ok := strings.Cut(base, "_")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("migration %s does not match NNNN_name.sql", fileName)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", fileName, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %s: up and down files have different names", version)
+		}
+		if down {
+			m.Down = string(content)
+		} else {
+			sum := sha256.Sum256(content)
+			m.Up = string(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s_%s has a down file but no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func (db *DB) ensureMigrationsTable(ctx context.Context) error {
+	var tracked bool
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&tracked)
+	if err != nil {
+		return fmt.Errorf("checking schema_migrations: %w", err)
+	}
+	if tracked {
+		return nil
+	}
+
+	var tables int
+	err = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`).Scan(&tables)
+	if err != nil {
+		return fmt.Errorf("checking existing tables: %w", err)
+	}
+	if tables > 0 {
+		return ErrUntrackedDatabase
+	}
+
+	return db.createMigrationsTable(ctx)
+}
+
+func (db *DB) createMigrationsTable(ctx context.Context) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TEXT NOT NULL DEFAULT (datetime('now'))
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// AppliedMigrations returns the migrations recorded in schema_migrations, oldest first
+func (db *DB) AppliedMigrations() ([]AppliedMigration, error) {
+	rows, err := db.Query(`SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("querying schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var m AppliedMigration
+		if err := rows.Scan(&m.Version, &m.Name, &m.Checksum, &m.AppliedAt); err != nil {
+			return nil, fmt.Errorf("scanning schema_migrations: %w", err)
+		}
+		applied = append(applied, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating schema_migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// Migrate applies every pending migration in order, each in its own
+// transaction, and returns the ones it applied. It refuses to run if an
+// applied migration was modified since.
+func (db *DB) Migrate(migrations []Migration) ([]Migration, error) {
+	ctx := context.Background()
+	if err := db.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := db.AppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string, len(applied))
+	for _, m := range applied {
+		checksums[m.Version] = m.Checksum
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		checksum, ok := checksums[m.Version]
+		if ok {
+			if checksum != m.Checksum {
+				return done, fmt.Errorf("migration %s_%s was modified after being applied", m.Version, m.Name)
+			}
+			continue
+		}
+
+		err := db.inMigrationTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`, m.Version, m.Name, m.Checksum)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("applying migration %s_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first, and
+// returns the ones it reverted
+func (db *DB) MigrateDown(migrations []Migration, steps int) ([]Migration, error) {
+	ctx := context.Background()
+	applied, err := db.AppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	var done []Migration
+	for i := len(applied) - 1; i >= 0 && len(done) < steps; i-- {
+		m, ok := byVersion[applied[i].Version]
+		if !ok {
+			return done, fmt.Errorf("migration %s_%s is applied but unknown to this binary", applied[i].Version, applied[i].Name)
+		}
+		if m.Down == "" {
+			return done, fmt.Errorf("migration %s_%s has no down migration", m.Version, m.Name)
+		}
+
+		err := db.inMigrationTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %s_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Baseline records every migration up to and including version as applied
+// without running it, for databases migrated before versions were tracked
+func (db *DB) Baseline(migrations []Migration, version string) error {
+	ctx := context.Background()
+	if err := db.createMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	found := false
+	for _, m := range migrations {
+		if m.Version > version {
+			break
+		}
+		found = found || m.Version == version
+		_, err := db.ExecContext(ctx, `INSERT OR IGNORE INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`, m.Version, m.Name, m.Checksum)
+		if err != nil {
+			return fmt.Errorf("recording migration %s_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown migration version %s", version)
+	}
+	return nil
+}
+
+// inMigrationTx runs fn in a transaction with foreign key enforcement
+// disabled, so migrations can rebuild tables, and checks foreign keys before
+// committing. The pragma cannot change inside a transaction, hence the
+// dedicated connection.
+func (db *DB) inMigrationTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	violations := rows.Next()
+	rows.Close()
+	if violations {
+		return errors.New("migration leaves foreign key violations")
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,46 @@
+// Package postgres implements the storage interfaces on PostgreSQL, used by
+// the production board while local development keeps SQLite.
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+type DB struct {
+	*sql.DB
+}
+
+// NewDB connects to the database at url, a postgres:// URL or a libpq
+// key=value connection string
+func NewDB(url string) (*DB, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+
+	return &DB{db}, nil
+}
+
+// Store returns the PostgreSQL Repository of this database
+func (db *DB) Store() storage.Store {
+	return NewRepository(db)
+}
+
+func (db *DB) Close() error {
+	return db.DB.Close()
+}
+
+var (
+	_ storage.Store    = (*Repository)(nil)
+	_ storage.Database = (*DB)(nil)
+)
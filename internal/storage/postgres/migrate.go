@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ddahon/workfromearth/database"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+// migrationLock is the advisory lock key serializing concurrent migrators,
+// e.g. the server and the scraper starting together with autoMigrate
+const migrationLock = 7264917
+
+// LoadMigrations reads the PostgreSQL migrations embedded in the binary, sorted by version
+func LoadMigrations() ([]storage.Migration, error) {
+	return storage.ReadMigrations(database.PostgresMigrations, "postgres")
+}
+
+// Migrations returns the PostgreSQL migrations embedded in the binary
+func (db *DB) Migrations() ([]storage.Migration, error) {
+	return LoadMigrations()
+}
+
+func (db *DB) createMigrationsTable(ctx context.Context) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// AppliedMigrations returns the migrations recorded in schema_migrations, oldest first
+func (db *DB) AppliedMigrations() ([]storage.AppliedMigration, error) {
+	ctx := context.Background()
+	if err := db.createMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("querying schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []storage.AppliedMigration
+	for rows.Next() {
+		var m storage.AppliedMigration
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&m.Version, &m.Name, &m.Checksum, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scanning schema_migrations: %w", err)
+		}
+		m.AppliedAt = formatTime(appliedAt)
+		applied = append(applied, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating schema_migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// Migrate applies every pending migration in order, each in its own
+// transaction, and returns the ones it applied. It refuses to run if an
+// applied migration was modified since.
+func (db *DB) Migrate(migrations []storage.Migration) ([]storage.Migration, error) {
+	ctx := context.Background()
+	applied, err := db.AppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string, len(applied))
+	for _, m := range applied {
+		checksums[m.Version] = m.Checksum
+	}
+
+	var done []storage.Migration
+	for _, m := range migrations {
+		checksum, ok := checksums[m.Version]
+		if ok {
+			if checksum != m.Checksum {
+				return done, fmt.Errorf("migration %s_%s was modified after being applied", m.Version, m.Name)
+			}
+			continue
+		}
+
+		ran := false
+		err := db.inMigrationTx(ctx, func(tx *sql.Tx) error {
+			// Another process may have applied it while we waited for the lock
+			var exists bool
+			err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, m.Version).Scan(&exists)
+			if err != nil || exists {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+				return err
+			}
+			ran = true
+			_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`, m.Version, m.Name, m.Checksum)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("applying migration %s_%s: %w", m.Version, m.Name, err)
+		}
+		if ran {
+			done = append(done, m)
+		}
+	}
+	return done, nil
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first, and
+// returns the ones it reverted
+func (db *DB) MigrateDown(migrations []storage.Migration, steps int) ([]storage.Migration, error) {
+	ctx := context.Background()
+	applied, err := db.AppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]storage.Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	var done []storage.Migration
+	for i := len(applied) - 1; i >= 0 && len(done) < steps; i-- {
+		m, ok := byVersion[applied[i].Version]
+		if !ok {
+			return done, fmt.Errorf("migration %s_%s is applied but unknown to this binary", applied[i].Version, applied[i].Name)
+		}
+		if m.Down == "" {
+			return done, fmt.Errorf("migration %s_%s has no down migration", m.Version, m.Name)
+		}
+
+		err := db.inMigrationTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %s_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Baseline records every migration up to and including version as applied
+// without running it, for a schema created by other means
+func (db *DB) Baseline(migrations []storage.Migration, version string) error {
+	ctx := context.Background()
+	if err := db.createMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	found := false
+	for _, m := range migrations {
+		if m.Version > version {
+			break
+		}
+		found = found || m.Version == version
+		_, err := db.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3) ON CONFLICT (version) DO NOTHING`, m.Version, m.Name, m.Checksum)
+		if err != nil {
+			return fmt.Errorf("recording migration %s_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown migration version %s", version)
+	}
+	return nil
+}
+
+// inMigrationTx runs fn in a transaction holding the migration lock.
+// PostgreSQL DDL is transactional, so a failed migration leaves no trace.
+func (db *DB) inMigrationTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLock); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
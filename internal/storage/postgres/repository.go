@@ -0,0 +1,723 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+type Repository struct {
+	db *DB
+}
+
+func NewRepository(db *DB) *Repository {
+	return &Repository{db: db}
+}
+
+// timeLayout matches SQLite's datetime('now'), so both backends report
+// timestamps the same way
+const timeLayout = "2006-01-02 15:04:05"
+
+func formatTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(timeLayout)
+}
+
+// parseTime reads a timestamp formatted by formatTime or by SQLite, in UTC
+func parseTime(s string) sql.NullTime {
+	for _, layout := range []string{"2006-01-02 15:04:05.000", timeLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return sql.NullTime{Time: t, Valid: true}
+		}
+	}
+	return sql.NullTime{}
+}
+
+const jobColumns = `
+	j.id,
+	j.title,
+	j.description,
+	j.job_url,
+	j.salary_range,
+	j.location,
+	j.published_at,
+	j.external_id,
+	j.apply_url,
+	j.employment_type,
+	j.department,
+	j.seniority,
+	j.category,
+	(SELECT string_agg(tag, ',' ORDER BY tag) FROM job_tags WHERE job_id = j.id),
+	j.language,
+	j.signals,
+	j.tz_min_offset,
+	j.tz_max_offset,
+	j.created_at,
+	j.updated_at,
+	c.id,
+	c.name,
+	c.site_url,
+	c.careers_url,
+	c.ats_type,
+	c.ats_url,
+	c.scraped_at,
+	c.created_at,
+	c.updated_at
+`
+
+const jobsOrder = ` ORDER BY j.published_at IS NULL, j.published_at DESC, j.created_at DESC`
+
+// scanJobRow scans a row selected with jobColumns into a Job struct
+func scanJobRow(rows rowScanner) (*scraping.Job, error) {
+	var j scraping.Job
+	var description, salaryRange, location, publishedAt, externalID, applyURL sql.NullString
+	var employmentType, department, seniority, category, tags, language, signals sql.NullString
+	var tzMin, tzMax sql.NullInt64
+	var companyID sql.NullInt64
+	var companyName, companySiteURL, companyCareersURL, companyATSType, companyATSUrl sql.NullString
+	var companyScrapedAt, companyCreatedAt, companyUpdatedAt sql.NullTime
+
+	err := rows.Scan(
+		&j.ID,
+		&j.Title,
+		&description,
+		&j.Url,
+		&salaryRange,
+		&location,
+		&publishedAt,
+		&externalID,
+		&applyURL,
+		&employmentType,
+		&department,
+		&seniority,
+		&category,
+		&tags,
+		&language,
+		&signals,
+		&tzMin,
+		&tzMax,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+		&companyID,
+		&companyName,
+		&companySiteURL,
+		&companyCareersURL,
+		&companyATSType,
+		&companyATSUrl,
+		&companyScrapedAt,
+		&companyCreatedAt,
+		&companyUpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Description = description.String
+	j.SalaryRange = salaryRange.String
+	j.Location = location.String
+	j.PublishedAt = publishedAt.String
+	j.ExternalID = externalID.String
+	j.ApplyURL = applyURL.String
+	j.EmploymentType = employmentType.String
+	j.Department = department.String
+	j.Seniority = seniority.String
+	j.Category = category.String
+	j.Tags = storage.SplitColumn(tags.String)
+	j.Language = language.String
+	j.Signals = storage.SplitColumn(signals.String)
+	if tzMin.Valid && tzMax.Valid {
+		j.Timezone = &scraping.TimezoneWindow{Min: int(tzMin.Int64), Max: int(tzMax.Int64)}
+	}
+
+	if companyID.Valid && companyName.Valid {
+		j.Company = &scraping.Company{
+			ID:         companyID.Int64,
+			Name:       companyName.String,
+			SiteURL:    companySiteURL.String,
+			CareersURL: companyCareersURL.String,
+			ATSType:    companyATSType.String,
+			ATSUrl:     companyATSUrl.String,
+			ScrapedAt:  formatTime(companyScrapedAt),
+			CreatedAt:  formatTime(companyCreatedAt),
+			UpdatedAt:  formatTime(companyUpdatedAt),
+		}
+	}
+
+	return &j, nil
+}
+
+func (r *Repository) queryJobs(query string, args ...any) ([]scraping.Job, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []scraping.Job
+	for rows.Next() {
+		j, err := scanJobRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning job: %w", err)
+		}
+		jobs = append(jobs, *j)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *Repository) SaveJobs(jobs []scraping.Job, companyID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.Prepare(`
+		INSERT INTO jobs (id, external_id, title, company, company_id, description, job_url, apply_url, salary_range, location, published_at, published_at_raw, employment_type, department, seniority, category, language, signals, tz_min_offset, tz_max_offset, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, (SELECT name FROM companies WHERE id = $4), $4, $5, $6, NULLIF($7, ''), $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''), NULLIF($15, ''), NULLIF($16, ''), NULLIF($17, ''), $18, $19, now())
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer insert.Close()
+
+	update, err := tx.Prepare(`
+		UPDATE jobs SET
+			external_id = COALESCE(NULLIF($1, ''), external_id),
+			title = $2,
+			company_id = $3,
+			description = $4,
+			job_url = $5,
+			apply_url = NULLIF($6, ''),
+			salary_range = $7,
+			location = $8,
+			employment_type = NULLIF($9, ''),
+			department = NULLIF($10, ''),
+			seniority = NULLIF($11, ''),
+			category = NULLIF($12, ''),
+			language = NULLIF($13, ''),
+			signals = NULLIF($14, ''),
+			tz_min_offset = $15,
+			tz_max_offset = $16,
+			-- Dates are kept from the first scrape, but replace the scrape time
+			-- stored when the ATS hadn't reported one
+			published_at = CASE WHEN published_at_raw IS NULL AND $18 <> '' THEN COALESCE(NULLIF($17, ''), published_at) ELSE COALESCE(published_at, NULLIF($17, '')) END,
+			published_at_raw = COALESCE(published_at_raw, NULLIF($18, '')),
+			updated_at = now()
+		WHERE id = $19
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer update.Close()
+
+	for _, job := range jobs {
+		existingID, err := storage.FindJobID(tx, companyID, job)
+		if err != nil {
+			return err
+		}
+		existingID, url, err := storage.ClaimJobURL(tx, companyID, existingID, job.Url)
+		if err != nil {
+			return err
+		}
+		if url == "" {
+			// Another company lists a job at the same URL
+			continue
+		}
+		job.Url = url
+
+		tzMin, tzMax := storage.TimezoneBounds(job.Timezone)
+		jobID := existingID
+		if jobID == "" {
+			jobID = uuid.New().String()
+			_, err = insert.Exec(jobID, job.ExternalID, job.Title, companyID, job.Description, job.Url, job.ApplyURL, job.SalaryRange, job.Location, job.PublishedAt, job.PublishedAtRaw, job.EmploymentType, job.Department, job.Seniority, job.Category, job.Language, strings.Join(job.Signals, ","), tzMin, tzMax)
+		} else {
+			if err := storage.RecordJobRevision(tx, existingID, job); err != nil {
+				return err
+			}
+			_, err = update.Exec(job.ExternalID, job.Title, companyID, job.Description, job.Url, job.ApplyURL, job.SalaryRange, job.Location, job.EmploymentType, job.Department, job.Seniority, job.Category, job.Language, strings.Join(job.Signals, ","), tzMin, tzMax, job.PublishedAt, job.PublishedAtRaw, existingID)
+		}
+		if err != nil {
+			return fmt.Errorf("executing statement: %w", err)
+		}
+
+		if err := storage.SaveJobTags(tx, jobID, job.Tags); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetAllJobs returns the jobs to list, leaving out duplicates of other jobs
+func (r *Repository) GetAllJobs() ([]scraping.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs j LEFT JOIN companies c ON j.company_id = c.id WHERE j.canonical_id IS NULL` + jobsOrder
+	return r.queryJobs(query)
+}
+
+// GetJobDuplicates returns the jobs the dedup stage linked to jobID as
+// duplicates of it
+func (r *Repository) GetJobDuplicates(jobID string) ([]scraping.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs j LEFT JOIN companies c ON j.company_id = c.id
+		WHERE j.canonical_id = $1 ORDER BY j.created_at, j.id`
+	return r.queryJobs(query, jobID)
+}
+
+// GetJobsForDedup returns every job, duplicates included, with the fields
+// compared by the dedup stage: title, description, location, company and
+// creation time
+func (r *Repository) GetJobsForDedup() ([]scraping.Job, error) {
+	rows, err := r.db.Query(`
+		SELECT j.id, j.title, j.description, j.location, j.created_at, c.id, c.name
+		FROM jobs j
+		LEFT JOIN companies c ON j.company_id = c.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []scraping.Job
+	for rows.Next() {
+		var j scraping.Job
+		var description, location, companyName sql.NullString
+		var companyID sql.NullInt64
+		if err := rows.Scan(&j.ID, &j.Title, &description, &location, &j.CreatedAt, &companyID, &companyName); err != nil {
+			return nil, fmt.Errorf("scanning job: %w", err)
+		}
+		j.Description = description.String
+		j.Location = location.String
+		if companyID.Valid {
+			j.Company = &scraping.Company{ID: companyID.Int64, Name: companyName.String}
+		}
+		jobs = append(jobs, j)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// SetCanonicalJobs links each job ID key of duplicates to the canonical job
+// it duplicates, and makes every other job canonical again
+func (r *Repository) SetCanonicalJobs(duplicates map[string]string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE jobs SET canonical_id = NULL WHERE canonical_id IS NOT NULL`); err != nil {
+		return fmt.Errorf("resetting canonical jobs: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`UPDATE jobs SET canonical_id = $1 WHERE id = $2`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for jobID, canonicalID := range duplicates {
+		if _, err := stmt.Exec(canonicalID, jobID); err != nil {
+			return fmt.Errorf("linking duplicate job: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetJobByID returns a job with its company
+func (r *Repository) GetJobByID(id string) (*scraping.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs j LEFT JOIN companies c ON j.company_id = c.id WHERE j.id = $1`
+	j, err := scanJobRow(r.db.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w with ID: %s", storage.ErrJobNotFound, id)
+		}
+		return nil, fmt.Errorf("scanning job: %w", err)
+	}
+	return j, nil
+}
+
+// GetJobRevisions returns the revisions of a job, newest first
+func (r *Repository) GetJobRevisions(jobID string) ([]storage.JobRevision, error) {
+	query := `
+		SELECT id, job_id, summary, changes, title, description, salary_range, location, created_at
+		FROM job_revisions
+		WHERE job_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := r.db.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("querying job revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []storage.JobRevision
+	for rows.Next() {
+		var rev storage.JobRevision
+		var changes, title, description, salaryRange, location sql.NullString
+		var createdAt sql.NullTime
+		err := rows.Scan(&rev.ID, &rev.JobID, &rev.Summary, &changes, &title, &description, &salaryRange, &location, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("scanning job revision: %w", err)
+		}
+		rev.Changes = storage.ParseRevisionChanges(changes.String)
+		rev.Title = title.String
+		rev.Description = description.String
+		rev.SalaryRange = salaryRange.String
+		rev.Location = location.String
+		rev.CreatedAt = formatTime(createdAt)
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating job revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// SearchJobs returns the jobs to list matching filter, its query matching
+// titles containing words starting with each of its terms, using the
+// full-text index on jobs.search
+func (r *Repository) SearchJobs(filter storage.JobFilter) ([]scraping.Job, error) {
+	conditions := []string{"j.canonical_id IS NULL"}
+	var args []any
+	if tsquery := prefixQuery(filter.Query); tsquery != "" {
+		args = append(args, tsquery)
+		conditions = append(conditions, "j.search @@ to_tsquery('simple', $1)")
+	}
+	filterConditions, args := filter.Conditions(args)
+	conditions = append(conditions, filterConditions...)
+
+	query := `SELECT ` + jobColumns + ` FROM jobs j LEFT JOIN companies c ON j.company_id = c.id
+		WHERE ` + strings.Join(conditions, " AND ") + jobsOrder
+	return r.queryJobs(query, args...)
+}
+
+// GetJobDepartments returns the departments of the listed jobs, sorted
+func (r *Repository) GetJobDepartments() ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT department FROM jobs
+		WHERE canonical_id IS NULL AND department IS NOT NULL
+		ORDER BY department
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying departments: %w", err)
+	}
+	defer rows.Close()
+
+	var departments []string
+	for rows.Next() {
+		var department string
+		if err := rows.Scan(&department); err != nil {
+			return nil, fmt.Errorf("scanning department: %w", err)
+		}
+		departments = append(departments, department)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating departments: %w", err)
+	}
+
+	return departments, nil
+}
+
+// prefixQuery turns free text into a tsquery requiring a prefix match of
+// every word, so partial words keep matching like the SQLite LIKE search
+func prefixQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+const companyColumns = `id, name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCompany scans a row selected with companyColumns into a Company struct
+func scanCompany(row rowScanner) (*scraping.Company, error) {
+	var c scraping.Company
+	var siteURL, careersURL, atsType, atsURL, tags sql.NullString
+	var scrapedAt, createdAt, updatedAt sql.NullTime
+
+	err := row.Scan(
+		&c.ID,
+		&c.Name,
+		&siteURL,
+		&careersURL,
+		&atsType,
+		&atsURL,
+		&c.Disabled,
+		&tags,
+		&scrapedAt,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.SiteURL = siteURL.String
+	c.CareersURL = careersURL.String
+	c.ATSType = atsType.String
+	c.ATSUrl = atsURL.String
+	c.Tags = storage.SplitColumn(tags.String)
+	c.ScrapedAt = formatTime(scrapedAt)
+	c.CreatedAt = formatTime(createdAt)
+	c.UpdatedAt = formatTime(updatedAt)
+
+	return &c, nil
+}
+
+func (r *Repository) GetCompanies() ([]scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies ORDER BY name`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("querying companies: %w", err)
+	}
+	defer rows.Close()
+
+	var companies []scraping.Company
+	for rows.Next() {
+		c, err := scanCompany(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning company: %w", err)
+		}
+		companies = append(companies, *c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating companies: %w", err)
+	}
+
+	return companies, nil
+}
+
+func (r *Repository) SaveCompany(company scraping.Company) (int64, error) {
+	return saveCompany(r.db, company)
+}
+
+// SaveCompanies saves companies like SaveCompany in one transaction, so
+// either all of them are saved or none is, and returns their IDs
+func (r *Repository) SaveCompanies(companies []scraping.Company) ([]int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, 0, len(companies))
+	for _, company := range companies {
+		id, err := saveCompany(tx, company)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", company.Name, err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return ids, nil
+}
+
+// execer runs statements on the database or inside one of its transactions
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// saveCompany is SaveCompany running its statements on db
+func saveCompany(db execer, company scraping.Company) (int64, error) {
+	tags := strings.Join(company.Tags, ",")
+	scrapedAt := parseTime(company.ScrapedAt)
+
+	if company.ID == 0 {
+		query := `
+			INSERT INTO companies (name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+			RETURNING id
+		`
+		var id int64
+		err := db.QueryRow(
+			query,
+			company.Name,
+			company.SiteURL,
+			company.CareersURL,
+			company.ATSType,
+			company.ATSUrl,
+			company.Disabled,
+			tags,
+			scrapedAt,
+		).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("saving company: %w", err)
+		}
+		return id, nil
+	}
+
+	query := `
+		UPDATE companies SET
+			name = $1,
+			site_url = $2,
+			careers_url = $3,
+			ats_type = $4,
+			ats_url = $5,
+			disabled = $6,
+			tags = $7,
+			scraped_at = $8,
+			updated_at = now()
+		WHERE id = $9
+	`
+	_, err := db.Exec(
+		query,
+		company.Name,
+		company.SiteURL,
+		company.CareersURL,
+		company.ATSType,
+		company.ATSUrl,
+		company.Disabled,
+		tags,
+		scrapedAt,
+		company.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("updating company: %w", err)
+	}
+	return company.ID, nil
+}
+
+func (r *Repository) UpdateScrapedAt(companyID int64) error {
+	query := `UPDATE companies SET scraped_at = now(), updated_at = now() WHERE id = $1`
+	if _, err := r.db.Exec(query, companyID); err != nil {
+		return fmt.Errorf("updating scraped_at: %w", err)
+	}
+	return nil
+}
+
+// GetBoardState returns the state the last scrape of a company's board left,
+// or the zero BoardState if it has none
+func (r *Repository) GetBoardState(companyID int64) (scraping.BoardState, error) {
+	var state scraping.BoardState
+	var etag, lastModified, contentHash sql.NullString
+	err := r.db.QueryRow(
+		`SELECT url, etag, last_modified, content_hash FROM board_states WHERE company_id = $1`,
+		companyID,
+	).Scan(&state.URL, &etag, &lastModified, &contentHash)
+	if err == sql.ErrNoRows {
+		return scraping.BoardState{}, nil
+	}
+	if err != nil {
+		return scraping.BoardState{}, fmt.Errorf("getting board state: %w", err)
+	}
+	state.ETag = etag.String
+	state.LastModified = lastModified.String
+	state.ContentHash = contentHash.String
+	return state, nil
+}
+
+// SaveBoardState records the state of a company's board for its next scrape
+func (r *Repository) SaveBoardState(companyID int64, state scraping.BoardState) error {
+	query := `
+		INSERT INTO board_states (company_id, url, etag, last_modified, content_hash, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), now())
+		ON CONFLICT (company_id) DO UPDATE SET
+			url = excluded.url,
+			etag = excluded.etag,
+			last_modified = excluded.last_modified,
+			content_hash = excluded.content_hash,
+			updated_at = excluded.updated_at
+	`
+	if _, err := r.db.Exec(query, companyID, state.URL, state.ETag, state.LastModified, state.ContentHash); err != nil {
+		return fmt.Errorf("saving board state: %w", err)
+	}
+	return nil
+}
+
+// SetCompanyDisabled toggles whether a company is skipped by the scraper
+func (r *Repository) SetCompanyDisabled(companyID int64, disabled bool) error {
+	query := `UPDATE companies SET disabled = $1, updated_at = now() WHERE id = $2`
+	if _, err := r.db.Exec(query, disabled, companyID); err != nil {
+		return fmt.Errorf("updating disabled: %w", err)
+	}
+	return nil
+}
+
+// DeleteCompany removes a company, its jobs are deleted by the foreign key cascade
+func (r *Repository) DeleteCompany(companyID int64) error {
+	if _, err := r.db.Exec(`DELETE FROM companies WHERE id = $1`, companyID); err != nil {
+		return fmt.Errorf("deleting company: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetCompanyByID(id int64) (*scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE id = $1`
+	c, err := scanCompany(r.db.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w with ID: %d", storage.ErrCompanyNotFound, id)
+		}
+		return nil, fmt.Errorf("scanning company: %w", err)
+	}
+	return c, nil
+}
+
+// GetCompanyByCareersURL looks a company up by its careers page only, unlike
+// GetCompanyByURL which also matches the ATS URL
+func (r *Repository) GetCompanyByCareersURL(careersURL string) (*scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE careers_url = $1`
+	c, err := scanCompany(r.db.QueryRow(query, careersURL))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w with careers URL: %s", storage.ErrCompanyNotFound, careersURL)
+		}
+		return nil, fmt.Errorf("scanning company: %w", err)
+	}
+	return c, nil
+}
+
+func (r *Repository) GetCompanyByURL(url string) (*scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE careers_url = $1 OR ats_url = $1`
+	c, err := scanCompany(r.db.QueryRow(query, url))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w with URL: %s", storage.ErrCompanyNotFound, url)
+		}
+		return nil, fmt.Errorf("scanning company: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,133 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ddahon/workfromearth/internal/storage"
+	"github.com/ddahon/workfromearth/internal/storage/postgres"
+	"github.com/ddahon/workfromearth/internal/storage/storagetest"
+)
+
+// TestStore runs the storage suite against the PostgreSQL server at
+// WFE_TEST_DBURL, e.g. postgres://localhost/wfe_test, each test in a schema
+// of its own dropped when it ends. make test-postgres starts one.
+func TestStore(t *testing.T) {
+	storagetest.RunStoreSuite(t, func(t *testing.T) (*sql.DB, storage.Store) {
+		db := newDB(t)
+		migrate(t, db, nil)
+		return db.DB, db.Store()
+	})
+}
+
+func TestNormalizePublishedAtMigration(t *testing.T) {
+	db := newDB(t)
+	migrate(t, db, func(m storage.Migration) bool { return m.Version < "0011" })
+
+	if _, err := db.Exec(`
+		INSERT INTO jobs (id, title, company, job_url, published_at) VALUES
+			('offset', 'Offset', 'Acme', 'https://acme.example/1', '2024-05-01T10:00:00+02:00'),
+			('recruitee', 'Recruitee', 'Acme', 'https://acme.example/2', '2024-05-01 10:00:00 UTC'),
+			('naive', 'Naive', 'Acme', 'https://acme.example/3', '2024-05-01 10:00:00'),
+			('out-of-range', 'Out of range', 'Acme', 'https://acme.example/4', '2024-13-45'),
+			('garbage', 'Garbage', 'Acme', 'https://acme.example/5', '2024-05-01 ??'),
+			('words', 'Words', 'Acme', 'https://acme.example/6', 'May 1st')
+	`); err != nil {
+		t.Fatalf("inserting jobs: %v", err)
+	}
+	migrate(t, db, nil)
+
+	want := map[string]sql.NullString{
+		"offset":       {String: "2024-05-01T08:00:00Z", Valid: true},
+		"recruitee":    {String: "2024-05-01T10:00:00Z", Valid: true},
+		"naive":        {String: "2024-05-01T10:00:00Z", Valid: true},
+		"out-of-range": {},
+		"garbage":      {},
+		"words":        {},
+	}
+	for id, published := range want {
+		var got, raw sql.NullString
+		if err := db.QueryRow(`SELECT published_at, published_at_raw FROM jobs WHERE id = $1`, id).Scan(&got, &raw); err != nil {
+			t.Fatalf("reading %s: %v", id, err)
+		}
+		if got != published {
+			t.Errorf("%s: published_at = %v, want %v", id, got, published)
+		}
+		if !raw.Valid {
+			t.Errorf("%s: raw date wasn't kept", id)
+		}
+	}
+}
+
+// newDB connects to a schema created for the test in the database at
+// WFE_TEST_DBURL, skipping the test when it isn't set
+func newDB(t *testing.T) *postgres.DB {
+	t.Helper()
+	dbURL := os.Getenv("WFE_TEST_DBURL")
+	if dbURL == "" {
+		t.Skip("WFE_TEST_DBURL is not set, run make test-postgres")
+	}
+
+	admin, err := postgres.NewDB(dbURL)
+	if err != nil {
+		t.Fatalf("connecting to test database: %v", err)
+	}
+	schema := fmt.Sprintf("wfe_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		admin.Close()
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("dropping schema: %v", err)
+		}
+		admin.Close()
+	})
+
+	db, err := postgres.NewDB(withSearchPath(dbURL, schema))
+	if err != nil {
+		t.Fatalf("connecting to test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// migrate applies the migrations keep returns true for, all of them when
+// keep is nil
+func migrate(t *testing.T, db *postgres.DB, keep func(m storage.Migration) bool) {
+	t.Helper()
+	migrations, err := db.Migrations()
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	var kept []storage.Migration
+	for _, m := range migrations {
+		if keep == nil || keep(m) {
+			kept = append(kept, m)
+		}
+	}
+	if _, err := db.Migrate(kept); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+}
+
+// withSearchPath sets the schema of the connections to dbURL, a postgres://
+// URL or a key=value connection string
+func withSearchPath(dbURL, schema string) string {
+	if !strings.Contains(dbURL, "://") {
+		return dbURL + " search_path=" + schema
+	}
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return dbURL
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+func (r *Repository) Stats() (storage.Stats, error) {
+	stats := storage.Stats{JobsByATS: map[string]int{}}
+	var lastScrapedAt sql.NullTime
+
+	err := r.db.QueryRow(`SELECT COUNT(*), COUNT(*) FILTER (WHERE disabled), MAX(scraped_at) FROM companies`).
+		Scan(&stats.Companies, &stats.DisabledCompanies, &lastScrapedAt)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("counting companies: %w", err)
+	}
+	stats.LastScrapedAt = formatTime(lastScrapedAt)
+
+	err = r.db.QueryRow(`
+		SELECT COUNT(*), COUNT(*) - COUNT(c.id)
+		FROM jobs j
+		LEFT JOIN companies c ON j.company_id = c.id
+	`).Scan(&stats.Jobs, &stats.JobsWithoutCompany)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("counting jobs: %w", err)
+	}
+
+	err = r.db.QueryRow(`SELECT COUNT(*) FROM company_submissions WHERE status = $1`, storage.SubmissionPending).
+		Scan(&stats.PendingSubmissions)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("counting submissions: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT COALESCE(NULLIF(c.ats_type, ''), 'unknown'), COUNT(*)
+		FROM jobs j
+		JOIN companies c ON j.company_id = c.id
+		GROUP BY 1
+	`)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("querying jobs by ATS: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var atsType string
+		var count int
+		if err := rows.Scan(&atsType, &count); err != nil {
+			return storage.Stats{}, fmt.Errorf("scanning jobs by ATS: %w", err)
+		}
+		stats.JobsByATS[atsType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return storage.Stats{}, fmt.Errorf("iterating jobs by ATS: %w", err)
+	}
+
+	return stats, nil
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +12,12 @@ import (
 	"github.com/ddahon/workfromearth/internal/scraping"
 )
 
+// ErrCompanyNotFound is returned when a company lookup matches no row
+var ErrCompanyNotFound = errors.New("no company found")
+
+// ErrJobNotFound is returned when a job lookup matches no row
+var ErrJobNotFound = errors.New("no job found")
+
 type Repository struct {
 	db *DB
 }
@@ -21,24 +29,40 @@ func NewRepository(db *DB) *Repository {
 // scanJobRow scans a database row into a Job struct, handling nullable fields
 // Expects columns in this order:
 // j.id, j.title, j.description, j.job_url, j.salary_range, j.location, j.published_at,
+// j.external_id, j.apply_url, j.employment_type, j.department, j.seniority, j.category,
+// the comma-separated tags, j.language, j.signals, j.tz_min_offset, j.tz_max_offset,
 // j.created_at, j.updated_at, c.id, c.name, c.site_url, c.careers_url, c.ats_type,
 // c.ats_url, c.scraped_at, c.created_at, c.updated_at
-func scanJobRow(rows *sql.Rows) (*scraping.Job, error) {
+func scanJobRow(rows rowScanner) (*scraping.Job, error) {
 	var j scraping.Job
 	var createdAt, updatedAt sql.NullString
 	var companyID sql.NullInt64
 	var companyName, companySiteURL, companyCareersURL, companyATSType, companyATSUrl sql.NullString
 	var companyScrapedAt, companyCreatedAt, companyUpdatedAt sql.NullString
-	var location sql.NullString
+	var description, salaryRange, location, publishedAt sql.NullString
+	var externalID, applyURL, employmentType, department sql.NullString
+	var seniority, category, tags, language, signals sql.NullString
+	var tzMin, tzMax sql.NullInt64
 
 	err := rows.Scan(
 		&j.ID,
 		&j.Title,
-		&j.Description,
+		&description,
 		&j.Url,
-		&j.SalaryRange,
+		&salaryRange,
 		&location,
-		&j.PublishedAt,
+		&publishedAt,
+		&externalID,
+		&applyURL,
+		&employmentType,
+		&department,
+		&seniority,
+		&category,
+		&tags,
+		&language,
+		&signals,
+		&tzMin,
+		&tzMax,
 		&createdAt,
 		&updatedAt,
 		&companyID,
@@ -55,37 +79,25 @@ func scanJobRow(rows *sql.Rows) (*scraping.Job, error) {
 		return nil, err
 	}
 
-	if location.Valid {
-		j.Location = location.String
+	j.Description = description.String
+	j.SalaryRange = salaryRange.String
+	j.Location = location.String
+	j.PublishedAt = publishedAt.String
+	j.ExternalID = externalID.String
+	j.ApplyURL = applyURL.String
+	j.EmploymentType = employmentType.String
+	j.Department = department.String
+	j.Seniority = seniority.String
+	j.Category = category.String
+	j.Tags = SplitColumn(tags.String)
+	j.Language = language.String
+	j.Signals = SplitColumn(signals.String)
+	if tzMin.Valid && tzMax.Valid {
+		j.Timezone = &scraping.TimezoneWindow{Min: int(tzMin.Int64), Max: int(tzMax.Int64)}
 	}
 
-	if createdAt.Valid && createdAt.String != "" {
-		formats := []string{
-			"2006-01-02 15:04:05.000",
-			"2006-01-02 15:04:05",
-			time.RFC3339,
-		}
-		for _, format := range formats {
-			if t, err := time.Parse(format, createdAt.String); err == nil {
-				j.CreatedAt = t
-				break
-			}
-		}
-	}
-
-	if updatedAt.Valid && updatedAt.String != "" {
-		formats := []string{
-			"2006-01-02 15:04:05.000",
-			"2006-01-02 15:04:05",
-			time.RFC3339,
-		}
-		for _, format := range formats {
-			if t, err := time.Parse(format, updatedAt.String); err == nil {
-				j.UpdatedAt = t
-				break
-			}
-		}
-	}
+	j.CreatedAt = parseTimestamp(createdAt.String)
+	j.UpdatedAt = parseTimestamp(updatedAt.String)
 
 	if companyID.Valid && companyName.Valid {
 		j.Company = &scraping.Company{
@@ -104,6 +116,8 @@ func scanJobRow(rows *sql.Rows) (*scraping.Job, error) {
 	return &j, nil
 }
 
+// SaveJobs inserts the scraped jobs of a company, or updates the stored
+// ones they correspond to, recording a revision when tracked fields change
 func (r *Repository) SaveJobs(jobs []scraping.Job, companyID int64) error {
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -111,28 +125,78 @@ func (r *Repository) SaveJobs(jobs []scraping.Job, companyID int64) error {
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO jobs (id, title, company, company_id, description, job_url, salary_range, location, published_at, updated_at)
-		VALUES ($1, $2, (SELECT name FROM companies WHERE id = $3), $3, $4, $5, $6, $7, $8, datetime('now'))
-		ON CONFLICT (job_url) DO UPDATE SET
-			title = EXCLUDED.title,
-			description = EXCLUDED.description,
-			salary_range = EXCLUDED.salary_range,
-			location = EXCLUDED.location,
-			published_at = published_at,
-			company_id = EXCLUDED.company_id,
+	insert, err := tx.Prepare(`
+		INSERT INTO jobs (id, external_id, title, company, company_id, description, job_url, apply_url, salary_range, location, published_at, published_at_raw, employment_type, department, seniority, category, language, signals, tz_min_offset, tz_max_offset, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, (SELECT name FROM companies WHERE id = $4), $4, $5, $6, NULLIF($7, ''), $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''), NULLIF($15, ''), NULLIF($16, ''), NULLIF($17, ''), $18, $19, datetime('now'))
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer insert.Close()
+
+	update, err := tx.Prepare(`
+		UPDATE jobs SET
+			external_id = COALESCE(NULLIF($1, ''), external_id),
+			title = $2,
+			company_id = $3,
+			description = $4,
+			job_url = $5,
+			apply_url = NULLIF($6, ''),
+			salary_range = $7,
+			location = $8,
+			employment_type = NULLIF($9, ''),
+			department = NULLIF($10, ''),
+			seniority = NULLIF($11, ''),
+			category = NULLIF($12, ''),
+			language = NULLIF($13, ''),
+			signals = NULLIF($14, ''),
+			tz_min_offset = $15,
+			tz_max_offset = $16,
+			-- Dates are kept from the first scrape, but replace the scrape time
+			-- stored when the ATS hadn't reported one
+			published_at = CASE WHEN published_at_raw IS NULL AND $18 <> '' THEN COALESCE(NULLIF($17, ''), published_at) ELSE COALESCE(published_at, NULLIF($17, '')) END,
+			published_at_raw = COALESCE(published_at_raw, NULLIF($18, '')),
 			updated_at = datetime('now')
+		WHERE id = $19
 	`)
 	if err != nil {
 		return fmt.Errorf("preparing statement: %w", err)
 	}
-	defer stmt.Close()
+	defer update.Close()
 
 	for _, job := range jobs {
-		id := uuid.New().String()
-		if _, err := stmt.Exec(id, job.Title, companyID, job.Description, job.Url, job.SalaryRange, job.Location, job.PublishedAt); err != nil {
+		existingID, err := FindJobID(tx, companyID, job)
+		if err != nil {
+			return err
+		}
+		existingID, url, err := ClaimJobURL(tx, companyID, existingID, job.Url)
+		if err != nil {
+			return err
+		}
+		if url == "" {
+			// Another company lists a job at the same URL
+			continue
+		}
+		job.Url = url
+
+		tzMin, tzMax := TimezoneBounds(job.Timezone)
+		jobID := existingID
+		if jobID == "" {
+			jobID = uuid.New().String()
+			_, err = insert.Exec(jobID, job.ExternalID, job.Title, companyID, job.Description, job.Url, job.ApplyURL, job.SalaryRange, job.Location, job.PublishedAt, job.PublishedAtRaw, job.EmploymentType, job.Department, job.Seniority, job.Category, job.Language, strings.Join(job.Signals, ","), tzMin, tzMax)
+		} else {
+			if err := RecordJobRevision(tx, existingID, job); err != nil {
+				return err
+			}
+			_, err = update.Exec(job.ExternalID, job.Title, companyID, job.Description, job.Url, job.ApplyURL, job.SalaryRange, job.Location, job.EmploymentType, job.Department, job.Seniority, job.Category, job.Language, strings.Join(job.Signals, ","), tzMin, tzMax, job.PublishedAt, job.PublishedAtRaw, existingID)
+		}
+		if err != nil {
 			return fmt.Errorf("executing statement: %w", err)
 		}
+
+		if err := SaveJobTags(tx, jobID, job.Tags); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -142,9 +206,48 @@ func (r *Repository) SaveJobs(jobs []scraping.Job, companyID int64) error {
 	return nil
 }
 
+const companyColumns = `id, name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCompany scans a row selected with companyColumns into a Company struct
+func scanCompany(row rowScanner) (*scraping.Company, error) {
+	var c scraping.Company
+	var siteURL, careersURL, atsType, atsURL, tags, scrapedAt sql.NullString
+
+	err := row.Scan(
+		&c.ID,
+		&c.Name,
+		&siteURL,
+		&careersURL,
+		&atsType,
+		&atsURL,
+		&c.Disabled,
+		&tags,
+		&scrapedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.SiteURL = siteURL.String
+	c.CareersURL = careersURL.String
+	c.ATSType = atsType.String
+	c.ATSUrl = atsURL.String
+	c.Tags = SplitColumn(tags.String)
+	c.ScrapedAt = scrapedAt.String
+
+	return &c, nil
+}
+
 func (r *Repository) GetCompanies() ([]scraping.Company, error) {
-	query := `SELECT id, name, site_url, careers_url, ats_type, ats_url, scraped_at, created_at, updated_at FROM companies`
-	rows, err := r.db.Query(query)
+	query := `SELECT ` + companyColumns + ` FROM companies ORDER BY name`
+	rows, err := r.db.reader.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("querying companies: %w", err)
 	}
@@ -152,31 +255,11 @@ func (r *Repository) GetCompanies() ([]scraping.Company, error) {
 
 	var companies []scraping.Company
 	for rows.Next() {
-		var c scraping.Company
-		var siteURL, careersURL, atsType, atsURL, scrapedAt sql.NullString
-
-		err := rows.Scan(
-			&c.ID,
-			&c.Name,
-			&siteURL,
-			&careersURL,
-			&atsType,
-			&atsURL,
-			&scrapedAt,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-		)
+		c, err := scanCompany(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scanning company: %w", err)
 		}
-
-		c.SiteURL = siteURL.String
-		c.CareersURL = careersURL.String
-		c.ATSType = atsType.String
-		c.ATSUrl = atsURL.String
-		c.ScrapedAt = scrapedAt.String
-
-		companies = append(companies, c)
+		companies = append(companies, *c)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -187,6 +270,40 @@ func (r *Repository) GetCompanies() ([]scraping.Company, error) {
 }
 
 func (r *Repository) SaveCompany(company scraping.Company) (int64, error) {
+	return saveCompany(r.db, company)
+}
+
+// SaveCompanies saves companies like SaveCompany in one transaction, so
+// either all of them are saved or none is, and returns their IDs
+func (r *Repository) SaveCompanies(companies []scraping.Company) ([]int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, 0, len(companies))
+	for _, company := range companies {
+		id, err := saveCompany(tx, company)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", company.Name, err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+	return ids, nil
+}
+
+// execer runs statements on the database or inside one of its transactions
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// saveCompany is SaveCompany running its statements on db
+func saveCompany(db execer, company scraping.Company) (int64, error) {
 	var query string
 	var result sql.Result
 	var err error
@@ -194,16 +311,18 @@ func (r *Repository) SaveCompany(company scraping.Company) (int64, error) {
 	if company.ID == 0 {
 		// Insert new company - let SQLite auto-generate the ID
 		query = `
-			INSERT INTO companies (name, site_url, careers_url, ats_type, ats_url, scraped_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, datetime('now'))
+			INSERT INTO companies (name, site_url, careers_url, ats_type, ats_url, disabled, tags, scraped_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, datetime('now'))
 		`
-		result, err = r.db.Exec(
+		result, err = db.Exec(
 			query,
 			company.Name,
 			company.SiteURL,
 			company.CareersURL,
 			company.ATSType,
 			company.ATSUrl,
+			company.Disabled,
+			strings.Join(company.Tags, ","),
 			company.ScrapedAt,
 		)
 		if err != nil {
@@ -223,17 +342,21 @@ func (r *Repository) SaveCompany(company scraping.Company) (int64, error) {
 				careers_url = $3,
 				ats_type = $4,
 				ats_url = $5,
-				scraped_at = $6,
+				disabled = $6,
+				tags = $7,
+				scraped_at = $8,
 				updated_at = datetime('now')
-			WHERE id = $7
+			WHERE id = $9
 		`
-		_, err = r.db.Exec(
+		_, err = db.Exec(
 			query,
 			company.Name,
 			company.SiteURL,
 			company.CareersURL,
 			company.ATSType,
 			company.ATSUrl,
+			company.Disabled,
+			strings.Join(company.Tags, ","),
 			company.ScrapedAt,
 			company.ID,
 		)
@@ -253,66 +376,141 @@ func (r *Repository) UpdateScrapedAt(companyID int64) error {
 	return nil
 }
 
-func (r *Repository) GetCompanyByURL(url string) (*scraping.Company, error) {
-	query := `SELECT id, name, site_url, careers_url, ats_type, ats_url, scraped_at, created_at, updated_at FROM companies WHERE careers_url = $1 OR ats_url = $1`
-	row := r.db.QueryRow(query, url)
+// GetBoardState returns the state the last scrape of a company's board left,
+// or the zero BoardState if it has none
+func (r *Repository) GetBoardState(companyID int64) (scraping.BoardState, error) {
+	var state scraping.BoardState
+	var etag, lastModified, contentHash sql.NullString
+	err := r.db.reader.QueryRow(
+		`SELECT url, etag, last_modified, content_hash FROM board_states WHERE company_id = $1`,
+		companyID,
+	).Scan(&state.URL, &etag, &lastModified, &contentHash)
+	if err == sql.ErrNoRows {
+		return scraping.BoardState{}, nil
+	}
+	if err != nil {
+		return scraping.BoardState{}, fmt.Errorf("getting board state: %w", err)
+	}
+	state.ETag = etag.String
+	state.LastModified = lastModified.String
+	state.ContentHash = contentHash.String
+	return state, nil
+}
 
-	var c scraping.Company
-	var siteURL, careersURL, atsType, atsURL, scrapedAt sql.NullString
+// SaveBoardState records the state of a company's board for its next scrape
+func (r *Repository) SaveBoardState(companyID int64, state scraping.BoardState) error {
+	query := `
+		INSERT INTO board_states (company_id, url, etag, last_modified, content_hash, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), datetime('now'))
+		ON CONFLICT (company_id) DO UPDATE SET
+			url = excluded.url,
+			etag = excluded.etag,
+			last_modified = excluded.last_modified,
+			content_hash = excluded.content_hash,
+			updated_at = excluded.updated_at
+	`
+	if _, err := r.db.Exec(query, companyID, state.URL, state.ETag, state.LastModified, state.ContentHash); err != nil {
+		return fmt.Errorf("saving board state: %w", err)
+	}
+	return nil
+}
 
-	err := row.Scan(
-		&c.ID,
-		&c.Name,
-		&siteURL,
-		&careersURL,
-		&atsType,
-		&atsURL,
-		&scrapedAt,
-		&c.CreatedAt,
-		&c.UpdatedAt,
-	)
+// SetCompanyDisabled toggles whether a company is skipped by the scraper
+func (r *Repository) SetCompanyDisabled(companyID int64, disabled bool) error {
+	query := `UPDATE companies SET disabled = $1, updated_at = datetime('now') WHERE id = $2`
+	_, err := r.db.Exec(query, disabled, companyID)
+	if err != nil {
+		return fmt.Errorf("updating disabled: %w", err)
+	}
+	return nil
+}
+
+// DeleteCompany removes a company, its jobs are deleted by the foreign key cascade
+func (r *Repository) DeleteCompany(companyID int64) error {
+	if _, err := r.db.Exec(`DELETE FROM companies WHERE id = $1`, companyID); err != nil {
+		return fmt.Errorf("deleting company: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetCompanyByID(id int64) (*scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE id = $1`
+	c, err := scanCompany(r.db.reader.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no company found with URL: %s", url)
+			return nil, fmt.Errorf("%w with ID: %d", ErrCompanyNotFound, id)
 		}
 		return nil, fmt.Errorf("scanning company: %w", err)
 	}
+	return c, nil
+}
 
-	c.SiteURL = siteURL.String
-	c.CareersURL = careersURL.String
-	c.ATSType = atsType.String
-	c.ATSUrl = atsURL.String
-	c.ScrapedAt = scrapedAt.String
+// GetCompanyByCareersURL looks a company up by its careers page only, unlike
+// GetCompanyByURL which also matches the ATS URL
+func (r *Repository) GetCompanyByCareersURL(careersURL string) (*scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE careers_url = $1`
+	c, err := scanCompany(r.db.reader.QueryRow(query, careersURL))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w with careers URL: %s", ErrCompanyNotFound, careersURL)
+		}
+		return nil, fmt.Errorf("scanning company: %w", err)
+	}
+	return c, nil
+}
 
-	return &c, nil
+func (r *Repository) GetCompanyByURL(url string) (*scraping.Company, error) {
+	query := `SELECT ` + companyColumns + ` FROM companies WHERE careers_url = $1 OR ats_url = $1`
+	c, err := scanCompany(r.db.reader.QueryRow(query, url))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w with URL: %s", ErrCompanyNotFound, url)
+		}
+		return nil, fmt.Errorf("scanning company: %w", err)
+	}
+	return c, nil
 }
 
-func (r *Repository) GetAllJobs() ([]scraping.Job, error) {
-	query := `
-		SELECT 
-			j.id, 
-			j.title, 
-			j.description, 
-			j.job_url, 
-			j.salary_range,
-			j.location,
-			j.published_at,
-			j.created_at, 
-			j.updated_at,
-			c.id as company_id,
-			c.name as company_name,
-			c.site_url as company_site_url,
-			c.careers_url as company_careers_url,
-			c.ats_type as company_ats_type,
-			c.ats_url as company_ats_url,
-			c.scraped_at as company_scraped_at,
-			c.created_at as company_created_at,
-			c.updated_at as company_updated_at
-		FROM jobs j
-		LEFT JOIN companies c ON j.company_id = c.id
-		ORDER BY j.published_at IS NULL, j.published_at DESC, j.created_at DESC
-	`
-	rows, err := r.db.Query(query)
+// jobSelect selects the columns expected by scanJobRow
+const jobSelect = `
+	SELECT
+		j.id,
+		j.title,
+		j.description,
+		j.job_url,
+		j.salary_range,
+		j.location,
+		j.published_at,
+		j.external_id,
+		j.apply_url,
+		j.employment_type,
+		j.department,
+		j.seniority,
+		j.category,
+		(SELECT group_concat(tag, ',' ORDER BY tag) FROM job_tags WHERE job_id = j.id),
+		j.language,
+		j.signals,
+		j.tz_min_offset,
+		j.tz_max_offset,
+		j.created_at,
+		j.updated_at,
+		c.id,
+		c.name,
+		c.site_url,
+		c.careers_url,
+		c.ats_type,
+		c.ats_url,
+		c.scraped_at,
+		c.created_at,
+		c.updated_at
+	FROM jobs j
+	LEFT JOIN companies c ON j.company_id = c.id
+`
+
+const jobsOrder = ` ORDER BY j.published_at IS NULL, j.published_at DESC, j.created_at DESC`
+
+func (r *Repository) queryJobs(query string, args ...any) ([]scraping.Job, error) {
+	rows, err := r.db.reader.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying jobs: %w", err)
 	}
@@ -334,41 +532,81 @@ func (r *Repository) GetAllJobs() ([]scraping.Job, error) {
 	return jobs, nil
 }
 
-func (r *Repository) SearchJobsByTitle(query string) ([]scraping.Job, error) {
-	baseQuery := `
-		SELECT 
-			j.id, 
-			j.title, 
-			j.description, 
-			j.job_url, 
-			j.salary_range,
-			j.location,
-			j.published_at,
-			j.created_at, 
-			j.updated_at,
-			c.id as company_id,
-			c.name as company_name,
-			c.site_url as company_site_url,
-			c.careers_url as company_careers_url,
-			c.ats_type as company_ats_type,
-			c.ats_url as company_ats_url,
-			c.scraped_at as company_scraped_at,
-			c.created_at as company_created_at,
-			c.updated_at as company_updated_at
-		FROM jobs j
-		LEFT JOIN companies c ON j.company_id = c.id
-	`
+// GetAllJobs returns the jobs to list, leaving out duplicates of other jobs
+func (r *Repository) GetAllJobs() ([]scraping.Job, error) {
+	return r.queryJobs(jobSelect + ` WHERE j.canonical_id IS NULL` + jobsOrder)
+}
 
-	var rows *sql.Rows
-	var err error
+// SearchJobs returns the jobs to list matching filter, its query matching
+// titles containing it
+func (r *Repository) SearchJobs(filter JobFilter) ([]scraping.Job, error) {
+	conditions := []string{"j.canonical_id IS NULL"}
+	var args []any
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		conditions = append(conditions, "LOWER(j.title) LIKE LOWER($1)")
+	}
+	filterConditions, args := filter.Conditions(args)
+	conditions = append(conditions, filterConditions...)
 
-	if query == "" {
-		rows, err = r.db.Query(baseQuery + " ORDER BY j.published_at IS NULL, j.published_at DESC, j.created_at DESC")
-	} else {
-		searchPattern := "%" + query + "%"
-		rows, err = r.db.Query(baseQuery+" WHERE LOWER(j.title) LIKE LOWER(?) ORDER BY j.published_at IS NULL, j.published_at DESC, j.created_at DESC", searchPattern)
+	return r.queryJobs(jobSelect+` WHERE `+strings.Join(conditions, " AND ")+jobsOrder, args...)
+}
+
+// GetJobDepartments returns the departments of the listed jobs, sorted
+func (r *Repository) GetJobDepartments() ([]string, error) {
+	rows, err := r.db.reader.Query(`
+		SELECT DISTINCT department FROM jobs
+		WHERE canonical_id IS NULL AND department IS NOT NULL
+		ORDER BY department
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("querying departments: %w", err)
+	}
+	defer rows.Close()
+
+	var departments []string
+	for rows.Next() {
+		var department string
+		if err := rows.Scan(&department); err != nil {
+			return nil, fmt.Errorf("scanning department: %w", err)
+		}
+		departments = append(departments, department)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating departments: %w", err)
+	}
+
+	return departments, nil
+}
+
+// GetJobByID returns a job with its company
+func (r *Repository) GetJobByID(id string) (*scraping.Job, error) {
+	j, err := scanJobRow(r.db.reader.QueryRow(jobSelect+` WHERE j.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w with ID: %s", ErrJobNotFound, id)
+		}
+		return nil, fmt.Errorf("scanning job: %w", err)
+	}
+	return j, nil
+}
+
+// GetJobDuplicates returns the jobs the dedup stage linked to jobID as
+// duplicates of it
+func (r *Repository) GetJobDuplicates(jobID string) ([]scraping.Job, error) {
+	return r.queryJobs(jobSelect+` WHERE j.canonical_id = $1 ORDER BY j.created_at, j.id`, jobID)
+}
+
+// GetJobsForDedup returns every job, duplicates included, with the fields
+// compared by the dedup stage: title, description, location, company and
+// creation time
+func (r *Repository) GetJobsForDedup() ([]scraping.Job, error) {
+	rows, err := r.db.reader.Query(`
+		SELECT j.id, j.title, j.description, j.location, j.created_at, c.id, c.name
+		FROM jobs j
+		LEFT JOIN companies c ON j.company_id = c.id
+	`)
 	if err != nil {
 		return nil, fmt.Errorf("querying jobs: %w", err)
 	}
@@ -376,11 +614,19 @@ func (r *Repository) SearchJobsByTitle(query string) ([]scraping.Job, error) {
 
 	var jobs []scraping.Job
 	for rows.Next() {
-		j, err := scanJobRow(rows)
-		if err != nil {
+		var j scraping.Job
+		var description, location, createdAt, companyName sql.NullString
+		var companyID sql.NullInt64
+		if err := rows.Scan(&j.ID, &j.Title, &description, &location, &createdAt, &companyID, &companyName); err != nil {
 			return nil, fmt.Errorf("scanning job: %w", err)
 		}
-		jobs = append(jobs, *j)
+		j.Description = description.String
+		j.Location = location.String
+		j.CreatedAt = parseTimestamp(createdAt.String)
+		if companyID.Valid {
+			j.Company = &scraping.Company{ID: companyID.Int64, Name: companyName.String}
+		}
+		jobs = append(jobs, j)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -389,3 +635,50 @@ func (r *Repository) SearchJobsByTitle(query string) ([]scraping.Job, error) {
 
 	return jobs, nil
 }
+
+// SetCanonicalJobs links each job ID key of duplicates to the canonical job
+// it duplicates, and makes every other job canonical again
+func (r *Repository) SetCanonicalJobs(duplicates map[string]string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE jobs SET canonical_id = NULL WHERE canonical_id IS NOT NULL`); err != nil {
+		return fmt.Errorf("resetting canonical jobs: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`UPDATE jobs SET canonical_id = $1 WHERE id = $2`)
+	if err != nil {
+		return fmt.Errorf("preparing statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for jobID, canonicalID := range duplicates {
+		if _, err := stmt.Exec(canonicalID, jobID); err != nil {
+			return fmt.Errorf("linking duplicate job: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// parseTimestamp parses the timestamps written by datetime('now')
+func parseTimestamp(s string) time.Time {
+	formats := []string{
+		"2006-01-02 15:04:05.000",
+		"2006-01-02 15:04:05",
+		time.RFC3339,
+	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
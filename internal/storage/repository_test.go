@@ -0,0 +1,49 @@
+package storage_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+	"github.com/ddahon/workfromearth/internal/storage/storagetest"
+)
+
+func newStore(t *testing.T) (*sql.DB, storage.Store) {
+	db := storagetest.NewDB(t)
+	return db.DB, db.Store()
+}
+
+func TestStore(t *testing.T) {
+	storagetest.RunStoreSuite(t, newStore)
+}
+
+func TestSaveCompaniesSavesAllOrNone(t *testing.T) {
+	db, store := newStore(t)
+	if _, err := db.Exec(`CREATE TRIGGER reject_broken BEFORE INSERT ON companies WHEN NEW.name = 'Broken' BEGIN SELECT RAISE(ABORT, 'broken company'); END`); err != nil {
+		t.Fatalf("creating trigger: %v", err)
+	}
+
+	_, err := store.SaveCompanies([]scraping.Company{
+		{Name: "Globex", CareersURL: "https://globex.example/careers"},
+		{Name: "Broken", CareersURL: "https://broken.example/careers"},
+	})
+	if err == nil {
+		t.Fatal("saving companies: got no error")
+	}
+	if _, err := store.GetCompanyByCareersURL("https://globex.example/careers"); !errors.Is(err, storage.ErrCompanyNotFound) {
+		t.Errorf("company saved before the failure: got error %v, want ErrCompanyNotFound", err)
+	}
+
+	ids, err := store.SaveCompanies([]scraping.Company{
+		{Name: "Globex", CareersURL: "https://globex.example/careers"},
+		{Name: "Initech", CareersURL: "https://initech.example/careers"},
+	})
+	if err != nil {
+		t.Fatalf("saving companies: %v", err)
+	}
+	if len(ids) != 2 || ids[0] == ids[1] {
+		t.Errorf("ids = %v, want two distinct IDs", ids)
+	}
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+)
+
+// Tracked fields of a job, as named in JobRevision.Changes
+const (
+	FieldTitle       = "title"
+	FieldSalary      = "salary"
+	FieldLocation    = "location"
+	FieldDescription = "description"
+)
+
+// How a revision changed a tracked field
+const (
+	ChangeAdded   = "added"
+	ChangeChanged = "changed"
+	ChangeRemoved = "removed"
+)
+
+// JobRevision records a change of a job's tracked fields by a scrape. It
+// holds the values the fields had before the change.
+type JobRevision struct {
+	ID      int64
+	JobID   string
+	Summary string
+	// Changes maps each field the revision changed, e.g. FieldTitle, to how
+	// it changed, e.g. ChangeChanged
+	Changes     map[string]string
+	Title       string
+	Description string
+	SalaryRange string
+	Location    string
+	CreatedAt   string
+}
+
+// trackedFields are compared by RevisionSummary, in the order they are reported
+var trackedFields = []struct {
+	label string
+	value func(scraping.Job) string
+}{
+	{FieldTitle, func(j scraping.Job) string { return j.Title }},
+	{FieldSalary, func(j scraping.Job) string { return j.SalaryRange }},
+	{FieldLocation, func(j scraping.Job) string { return j.Location }},
+	{FieldDescription, func(j scraping.Job) string { return j.Description }},
+}
+
+// fieldChange is how a revision changed one tracked field
+type fieldChange struct {
+	field string
+	kind  string
+}
+
+// revisionChanges returns the tracked fields which differ between previous
+// and current, in the order of trackedFields
+func revisionChanges(previous, current scraping.Job) []fieldChange {
+	var changes []fieldChange
+	for _, field := range trackedFields {
+		before := strings.TrimSpace(field.value(previous))
+		after := strings.TrimSpace(field.value(current))
+		switch {
+		case before == after:
+			continue
+		case before == "":
+			changes = append(changes, fieldChange{field.label, ChangeAdded})
+		case after == "":
+			changes = append(changes, fieldChange{field.label, ChangeRemoved})
+		default:
+			changes = append(changes, fieldChange{field.label, ChangeChanged})
+		}
+	}
+	return changes
+}
+
+// RevisionSummary describes which tracked fields differ between previous
+// and current, e.g. "salary added, title changed", or returns "" if none do
+func RevisionSummary(previous, current scraping.Job) string {
+	return summarizeChanges(revisionChanges(previous, current))
+}
+
+func summarizeChanges(changes []fieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		parts = append(parts, change.field+" "+change.kind)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// encodeChanges formats changes for the changes column of job_revisions,
+// e.g. "salary:added,title:changed"
+func encodeChanges(changes []fieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		parts = append(parts, change.field+":"+change.kind)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseRevisionChanges decodes the changes column of job_revisions into
+// JobRevision.Changes
+func ParseRevisionChanges(s string) map[string]string {
+	changes := make(map[string]string)
+	for _, part := range SplitColumn(s) {
+		if field, kind, ok := strings.Cut(part, ":"); ok {
+			changes[field] = kind
+		}
+	}
+	return changes
+}
+
+// FindJobID returns the ID of the stored job a scraped job of the company
+// corresponds to, matching its ATS ID first and its URL second, or "" if
+// the job is new. The SQL is shared by both backends.
+func FindJobID(tx *sql.Tx, companyID int64, job scraping.Job) (string, error) {
+	var id string
+	if job.ExternalID != "" {
+		err := tx.QueryRow(`SELECT id FROM jobs WHERE company_id = $1 AND external_id = $2`, companyID, job.ExternalID).Scan(&id)
+		if err == nil {
+			return id, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("finding job by external ID: %w", err)
+		}
+	}
+
+	err := tx.QueryRow(`SELECT id FROM jobs WHERE job_url = $1 AND company_id = $2`, job.Url, companyID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("finding job by URL: %w", err)
+	}
+	return id, nil
+}
+
+// ClaimJobURL frees url for the stored job jobID of the company, "" for a
+// new job, and returns the ID and URL to save the job at. A job saved before
+// jobs were linked to companies still at url is the same posting: a new job
+// takes it over, linking it to the company, and a stored job replaces it.
+// Another job of the company still at url is a stale copy of the same
+// posting and is deleted. A job of another company keeps url, so the stored
+// job keeps its current URL and a new job isn't saved, ClaimJobURL returning
+// "". The SQL is shared by both backends.
+func ClaimJobURL(tx *sql.Tx, companyID int64, jobID, url string) (string, string, error) {
+	var holderID string
+	var holderCompanyID sql.NullInt64
+	err := tx.QueryRow(`SELECT id, company_id FROM jobs WHERE job_url = $1`, url).Scan(&holderID, &holderCompanyID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && holderID == jobID) {
+		return jobID, url, nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("finding job by URL: %w", err)
+	}
+
+	if !holderCompanyID.Valid && jobID == "" {
+		if _, err := tx.Exec(`UPDATE jobs SET company_id = $1 WHERE id = $2`, companyID, holderID); err != nil {
+			return "", "", fmt.Errorf("linking job to company: %w", err)
+		}
+		return holderID, url, nil
+	}
+	if !holderCompanyID.Valid || holderCompanyID.Int64 == companyID {
+		if _, err := tx.Exec(`DELETE FROM jobs WHERE id = $1`, holderID); err != nil {
+			return "", "", fmt.Errorf("deleting stale job: %w", err)
+		}
+		return jobID, url, nil
+	}
+
+	log.Printf("job URL %s of company %d is taken by job %s of company %d", url, companyID, holderID, holderCompanyID.Int64)
+	if jobID == "" {
+		return "", "", nil
+	}
+	var current string
+	if err := tx.QueryRow(`SELECT job_url FROM jobs WHERE id = $1`, jobID).Scan(&current); err != nil {
+		return "", "", fmt.Errorf("reading job URL: %w", err)
+	}
+	return jobID, current, nil
+}
+
+// RecordJobRevision saves a revision of the stored job jobID when the
+// scraped job changes any tracked field. It must run in the transaction
+// updating the job, before the update.
+func RecordJobRevision(tx *sql.Tx, jobID string, job scraping.Job) error {
+	var previous scraping.Job
+	var description, salaryRange, location sql.NullString
+
+	err := tx.QueryRow(`SELECT title, description, salary_range, location FROM jobs WHERE id = $1`, jobID).
+		Scan(&previous.Title, &description, &salaryRange, &location)
+	if err != nil {
+		return fmt.Errorf("reading previous job: %w", err)
+	}
+	previous.Description = description.String
+	previous.SalaryRange = salaryRange.String
+	previous.Location = location.String
+
+	changes := revisionChanges(previous, job)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO job_revisions (job_id, summary, changes, title, description, salary_range, location)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, jobID, summarizeChanges(changes), encodeChanges(changes), previous.Title, previous.Description, previous.SalaryRange, previous.Location)
+	if err != nil {
+		return fmt.Errorf("saving job revision: %w", err)
+	}
+	return nil
+}
+
+// GetJobRevisions returns the revisions of a job, newest first
+func (r *Repository) GetJobRevisions(jobID string) ([]JobRevision, error) {
+	query := `
+		SELECT id, job_id, summary, changes, title, description, salary_range, location, created_at
+		FROM job_revisions
+		WHERE job_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := r.db.reader.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("querying job revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []JobRevision
+	for rows.Next() {
+		var rev JobRevision
+		var changes, title, description, salaryRange, location sql.NullString
+		err := rows.Scan(&rev.ID, &rev.JobID, &rev.Summary, &changes, &title, &description, &salaryRange, &location, &rev.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scanning job revision: %w", err)
+		}
+		rev.Changes = ParseRevisionChanges(changes.String)
+		rev.Title = title.String
+		rev.Description = description.String
+		rev.SalaryRange = salaryRange.String
+		rev.Location = location.String
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating job revisions: %w", err)
+	}
+
+	return revisions, nil
+}
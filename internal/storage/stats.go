@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Stats summarizes the content of the database
+type Stats struct {
+	Companies          int
+	DisabledCompanies  int
+	Jobs               int
+	JobsWithoutCompany int
+	JobsByATS          map[string]int
+	PendingSubmissions int
+	LastScrapedAt      string
+}
+
+func (r *Repository) Stats() (Stats, error) {
+	stats := Stats{JobsByATS: map[string]int{}}
+	var lastScrapedAt sql.NullString
+
+	err := r.db.reader.QueryRow(`SELECT COUNT(*), COALESCE(SUM(disabled), 0), MAX(scraped_at) FROM companies`).
+		Scan(&stats.Companies, &stats.DisabledCompanies, &lastScrapedAt)
+	if err != nil {
+		return Stats{}, fmt.Errorf("counting companies: %w", err)
+	}
+
+	err = r.db.reader.QueryRow(`
+		SELECT COUNT(*), COUNT(*) - COUNT(c.id)
+		FROM jobs j
+		LEFT JOIN companies c ON j.company_id = c.id
+	`).Scan(&stats.Jobs, &stats.JobsWithoutCompany)
+	if err != nil {
+		return Stats{}, fmt.Errorf("counting jobs: %w", err)
+	}
+
+	err = r.db.reader.QueryRow(`SELECT COUNT(*) FROM company_submissions WHERE status = $1`, SubmissionPending).
+		Scan(&stats.PendingSubmissions)
+	if err != nil {
+		return Stats{}, fmt.Errorf("counting submissions: %w", err)
+	}
+	stats.LastScrapedAt = lastScrapedAt.String
+
+	rows, err := r.db.reader.Query(`
+		SELECT COALESCE(NULLIF(c.ats_type, ''), 'unknown'), COUNT(*)
+		FROM jobs j
+		JOIN companies c ON j.company_id = c.id
+		GROUP BY 1
+	`)
+	if err != nil {
+		return Stats{}, fmt.Errorf("querying jobs by ATS: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var atsType string
+		var count int
+		if err := rows.Scan(&atsType, &count); err != nil {
+			return Stats{}, fmt.Errorf("scanning jobs by ATS: %w", err)
+		}
+		stats.JobsByATS[atsType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return Stats{}, fmt.Errorf("iterating jobs by ATS: %w", err)
+	}
+
+	return stats, nil
+}
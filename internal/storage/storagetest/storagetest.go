@@ -0,0 +1,31 @@
+// Package storagetest provides databases for the tests of code using storage
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/ddahon/workfromearth/internal/config"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+// NewDB returns an in-memory SQLite database with every migration applied,
+// closed when the test ends
+func NewDB(t testing.TB) *storage.DB {
+	t.Helper()
+	// An in-memory database lives and dies with its connection, so the pool
+	// must keep exactly one open
+	db, err := storage.NewDB(":memory:", config.SQLiteConfig{JournalMode: "memory", Synchronous: "off", MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := db.Migrations()
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if _, err := db.Migrate(migrations); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+	return db
+}
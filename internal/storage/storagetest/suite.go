@@ -0,0 +1,524 @@
+package storagetest
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+)
+
+// NewStoreFunc returns the store of a fresh database with every migration
+// applied, and the database for the checks writing rows the store can't
+type NewStoreFunc func(t *testing.T) (*sql.DB, storage.Store)
+
+// storeTests are the checks RunStoreSuite runs
+var storeTests = []struct {
+	name string
+	run  func(t *testing.T, newStore NewStoreFunc)
+}{
+	{"SaveJobsUpdatesJobsWithTheSameURL", testSaveJobsUpdatesJobsWithTheSameURL},
+	{"SaveJobsMatchesExternalIDsBeforeURLs", testSaveJobsMatchesExternalIDsBeforeURLs},
+	{"SaveJobsLeavesJobsOfOtherCompanies", testSaveJobsLeavesJobsOfOtherCompanies},
+	{"SaveJobsLinksJobsWithoutCompany", testSaveJobsLinksJobsWithoutCompany},
+	{"SaveJobsReplacesStaleJobAtNewURL", testSaveJobsReplacesStaleJobAtNewURL},
+	{"SaveJobsKeepsPublishedAt", testSaveJobsKeepsPublishedAt},
+	{"SaveJobsReplacesScrapeTimeWithReportedDate", testSaveJobsReplacesScrapeTimeWithReportedDate},
+	{"SaveJobsReplacesTags", testSaveJobsReplacesTags},
+	{"SaveJobsRoundTripsEnrichedFields", testSaveJobsRoundTripsEnrichedFields},
+	{"GetCompanyByURL", testGetCompanyByURL},
+	{"CompanyTagsRoundTrip", testCompanyTagsRoundTrip},
+	{"JobsWithoutCompany", testJobsWithoutCompany},
+	{"SearchJobsOrder", testSearchJobsOrder},
+	{"SearchJobsLeavesOutDuplicates", testSearchJobsLeavesOutDuplicates},
+	{"SearchJobsFilters", testSearchJobsFilters},
+	{"ApproveSubmission", testApproveSubmission},
+	{"RejectSubmission", testRejectSubmission},
+	{"BoardStates", testBoardStates},
+}
+
+// RunStoreSuite checks that the stores of newStore behave as every storage
+// backend must, so that the SQLite and PostgreSQL queries don't drift apart
+func RunStoreSuite(t *testing.T, newStore NewStoreFunc) {
+	for _, test := range storeTests {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, newStore)
+		})
+	}
+}
+
+// setup returns the store of a fresh database from newStore with one
+// company, whose ID it also returns
+func setup(t *testing.T, newStore NewStoreFunc) (*sql.DB, storage.Store, int64) {
+	t.Helper()
+	db, store := newStore(t)
+	companyID, err := store.SaveCompany(scraping.Company{
+		Name:       "Acme",
+		CareersURL: "https://acme.example/careers",
+		ATSType:    "ashby",
+		ATSUrl:     "https://api.ashbyhq.com/posting-api/job-board/acme",
+	})
+	if err != nil {
+		t.Fatalf("saving company: %v", err)
+	}
+	return db, store, companyID
+}
+
+func saveJobs(t *testing.T, store storage.Store, companyID int64, jobs ...scraping.Job) {
+	t.Helper()
+	if err := store.SaveJobs(jobs, companyID); err != nil {
+		t.Fatalf("saving jobs: %v", err)
+	}
+}
+
+func allJobs(t *testing.T, store storage.Store) []scraping.Job {
+	t.Helper()
+	jobs, err := store.GetAllJobs()
+	if err != nil {
+		t.Fatalf("getting jobs: %v", err)
+	}
+	return jobs
+}
+
+func titles(jobs []scraping.Job) []string {
+	var titles []string
+	for _, job := range jobs {
+		titles = append(titles, job.Title)
+	}
+	return titles
+}
+
+func testSaveJobsUpdatesJobsWithTheSameURL(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saveJobs(t, store, companyID,
+		scraping.Job{Title: "Backend Engineer", Url: "https://acme.example/jobs/1", SalaryRange: "€80K"},
+		scraping.Job{Title: "Designer", Url: "https://acme.example/jobs/2"},
+	)
+	first := allJobs(t, store)
+
+	saveJobs(t, store, companyID, scraping.Job{Title: "Senior Backend Engineer", Url: "https://acme.example/jobs/1", SalaryRange: "€80K"})
+
+	jobs := allJobs(t, store)
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want the 2 saved first", len(jobs))
+	}
+	updated, err := store.GetJobByID(jobIDByURL(t, first, "https://acme.example/jobs/1"))
+	if err != nil {
+		t.Fatalf("getting job: %v", err)
+	}
+	if updated.Title != "Senior Backend Engineer" {
+		t.Errorf("title = %q, want the rescraped one", updated.Title)
+	}
+	if updated.Company == nil || updated.Company.ID != companyID {
+		t.Errorf("company = %+v, want company %d", updated.Company, companyID)
+	}
+
+	revisions, err := store.GetJobRevisions(updated.ID)
+	if err != nil {
+		t.Fatalf("getting revisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Summary != "title changed" ||
+		len(revisions[0].Changes) != 1 || revisions[0].Changes[storage.FieldTitle] != storage.ChangeChanged ||
+		revisions[0].Title != "Backend Engineer" {
+		t.Errorf("revisions = %+v, want one recording the previous title", revisions)
+	}
+}
+
+func testSaveJobsMatchesExternalIDsBeforeURLs(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saveJobs(t, store, companyID, scraping.Job{ExternalID: "42", Title: "Backend Engineer", Url: "https://acme.example/jobs/old"})
+	before := allJobs(t, store)
+
+	saveJobs(t, store, companyID, scraping.Job{ExternalID: "42", Title: "Backend Engineer", Url: "https://acme.example/jobs/new"})
+
+	after := allJobs(t, store)
+	if len(after) != 1 {
+		t.Fatalf("got %d jobs, want the job moved to its new URL", len(after))
+	}
+	if after[0].ID != before[0].ID || after[0].Url != "https://acme.example/jobs/new" {
+		t.Errorf("job = %s at %s, want %s at the new URL", after[0].ID, after[0].Url, before[0].ID)
+	}
+}
+
+func testSaveJobsLeavesJobsOfOtherCompanies(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	otherID, err := store.SaveCompany(scraping.Company{Name: "Globex", CareersURL: "https://globex.example/careers"})
+	if err != nil {
+		t.Fatalf("saving company: %v", err)
+	}
+	taken := "https://jobs.example/shared/1"
+	saveJobs(t, store, otherID, scraping.Job{Title: "Globex Engineer", Url: taken})
+	saveJobs(t, store, companyID, scraping.Job{ExternalID: "42", Title: "Acme Engineer", Url: "https://acme.example/jobs/42"})
+
+	// Neither a new job nor a moved one takes the URL of another company's job
+	saveJobs(t, store, companyID,
+		scraping.Job{Title: "Acme Designer", Url: taken},
+		scraping.Job{ExternalID: "42", Title: "Senior Acme Engineer", Url: taken},
+	)
+
+	jobs := allJobs(t, store)
+	if len(jobs) != 2 {
+		t.Fatalf("jobs = %v, want the Globex job and the Acme engineer", titles(jobs))
+	}
+	for _, job := range jobs {
+		switch job.Url {
+		case taken:
+			if job.Title != "Globex Engineer" || job.Company == nil || job.Company.ID != otherID {
+				t.Errorf("job at %s = %q of %+v, want Globex's", taken, job.Title, job.Company)
+			}
+		case "https://acme.example/jobs/42":
+			if job.Title != "Senior Acme Engineer" {
+				t.Errorf("title = %q, want the rescraped one at the previous URL", job.Title)
+			}
+		default:
+			t.Errorf("unexpected job %q at %s", job.Title, job.Url)
+		}
+	}
+}
+
+func testSaveJobsLinksJobsWithoutCompany(t *testing.T, newStore NewStoreFunc) {
+	db, store, companyID := setup(t, newStore)
+	url := "https://acme.example/jobs/legacy"
+	// Saved before jobs were linked to companies
+	if _, err := db.Exec(`INSERT INTO jobs (id, title, company, job_url) VALUES ('legacy', 'Engineer', 'Acme', $1)`, url); err != nil {
+		t.Fatalf("inserting job: %v", err)
+	}
+
+	saveJobs(t, store, companyID, scraping.Job{Title: "Backend Engineer", Url: url})
+
+	jobs := allJobs(t, store)
+	if len(jobs) != 1 {
+		t.Fatalf("jobs = %v, want the legacy job only", titles(jobs))
+	}
+	job := jobs[0]
+	if job.ID != "legacy" || job.Title != "Backend Engineer" || job.Company == nil || job.Company.ID != companyID {
+		t.Errorf("job = %q %q of %+v, want the legacy job rescraped and linked to Acme", job.ID, job.Title, job.Company)
+	}
+}
+
+func testSaveJobsReplacesStaleJobAtNewURL(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saveJobs(t, store, companyID,
+		scraping.Job{ExternalID: "42", Title: "Backend Engineer", Url: "https://acme.example/jobs/old"},
+		// Saved before the ATS reported IDs
+		scraping.Job{Title: "Backend Engineer", Url: "https://acme.example/jobs/new"},
+	)
+	before := allJobs(t, store)
+
+	saveJobs(t, store, companyID, scraping.Job{ExternalID: "42", Title: "Backend Engineer", Url: "https://acme.example/jobs/new"})
+
+	after := allJobs(t, store)
+	if len(after) != 1 {
+		t.Fatalf("got %d jobs, want the stale one replaced", len(after))
+	}
+	if want := jobIDByURL(t, before, "https://acme.example/jobs/old"); after[0].ID != want || after[0].Url != "https://acme.example/jobs/new" {
+		t.Errorf("job = %s at %s, want %s at the new URL", after[0].ID, after[0].Url, want)
+	}
+}
+
+func testSaveJobsKeepsPublishedAt(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	url := "https://acme.example/jobs/1"
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, PublishedAt: "2024-05-01T08:00:00Z", PublishedAtRaw: "2024-05-01T10:00:00+02:00"})
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, PublishedAt: "2024-06-01T08:00:00Z", PublishedAtRaw: "2024-06-01T10:00:00+02:00"})
+
+	if got := allJobs(t, store)[0].PublishedAt; got != "2024-05-01T08:00:00Z" {
+		t.Errorf("published at = %q, want the date of the first scrape", got)
+	}
+}
+
+func testSaveJobsReplacesScrapeTimeWithReportedDate(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	url := "https://acme.example/jobs/1"
+	// Without a date from the ATS, jobs are dated with the scrape time
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, PublishedAt: "2024-07-01T00:00:00Z"})
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, PublishedAt: "2024-05-01T10:00:00Z", PublishedAtRaw: "1714557600000"})
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, PublishedAt: "2024-08-01T00:00:00Z"})
+
+	if got := allJobs(t, store)[0].PublishedAt; got != "2024-05-01T10:00:00Z" {
+		t.Errorf("published at = %q, want the date the ATS reported", got)
+	}
+}
+
+func testSaveJobsReplacesTags(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	url := "https://acme.example/jobs/1"
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, Tags: []string{"kubernetes", "go"}})
+	if got := allJobs(t, store)[0].Tags; !slices.Equal(got, []string{"go", "kubernetes"}) {
+		t.Errorf("tags = %v, want [go kubernetes]", got)
+	}
+
+	saveJobs(t, store, companyID, scraping.Job{Title: "Engineer", Url: url, Tags: []string{"aws"}})
+	if got := allJobs(t, store)[0].Tags; !slices.Equal(got, []string{"aws"}) {
+		t.Errorf("tags = %v, want the rescraped [aws]", got)
+	}
+}
+
+func testSaveJobsRoundTripsEnrichedFields(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saved := scraping.Job{
+		ExternalID:     "7",
+		Title:          "Engineer",
+		Url:            "https://acme.example/jobs/7",
+		ApplyURL:       "https://acme.example/jobs/7/apply",
+		EmploymentType: scraping.EmploymentContract,
+		Department:     "Platform",
+		Seniority:      "senior",
+		Category:       "engineering",
+		Language:       "de",
+		Signals:        []string{scraping.SignalLocationRestricted, scraping.SignalRelocation},
+		Timezone:       &scraping.TimezoneWindow{Min: -60, Max: 180},
+	}
+	saveJobs(t, store, companyID, saved)
+
+	got := allJobs(t, store)[0]
+	if got.ExternalID != saved.ExternalID || got.ApplyURL != saved.ApplyURL || got.EmploymentType != saved.EmploymentType ||
+		got.Department != saved.Department || got.Seniority != saved.Seniority || got.Category != saved.Category || got.Language != saved.Language {
+		t.Errorf("job = %+v, want the fields of %+v", got, saved)
+	}
+	if !slices.Equal(got.Signals, saved.Signals) {
+		t.Errorf("signals = %v, want %v", got.Signals, saved.Signals)
+	}
+	if got.Timezone == nil || *got.Timezone != *saved.Timezone {
+		t.Errorf("timezone = %v, want %v", got.Timezone, saved.Timezone)
+	}
+}
+
+func testCompanyTagsRoundTrip(t *testing.T, newStore NewStoreFunc) {
+	_, store, _ := setup(t, newStore)
+	saved := []string{"fintech", "b2b; saas"}
+	companyID, err := store.SaveCompany(scraping.Company{Name: "Globex", CareersURL: "https://globex.example/careers", Tags: saved})
+	if err != nil {
+		t.Fatalf("saving company: %v", err)
+	}
+	company, err := store.GetCompanyByID(companyID)
+	if err != nil {
+		t.Fatalf("getting company: %v", err)
+	}
+	if !slices.Equal(company.Tags, saved) {
+		t.Errorf("tags = %q, want %q as saved", company.Tags, saved)
+	}
+}
+
+func testGetCompanyByURL(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+
+	for _, url := range []string{"https://acme.example/careers", "https://api.ashbyhq.com/posting-api/job-board/acme"} {
+		company, err := store.GetCompanyByURL(url)
+		if err != nil {
+			t.Errorf("%s: %v", url, err)
+			continue
+		}
+		if company.ID != companyID || company.Name != "Acme" {
+			t.Errorf("%s: got company %d %q, want %d", url, company.ID, company.Name, companyID)
+		}
+	}
+
+	if _, err := store.GetCompanyByURL("https://other.example/careers"); !errors.Is(err, storage.ErrCompanyNotFound) {
+		t.Errorf("unknown URL: got error %v, want ErrCompanyNotFound", err)
+	}
+}
+
+func testJobsWithoutCompany(t *testing.T, newStore NewStoreFunc) {
+	db, store, _ := setup(t, newStore)
+	// Jobs saved before companies were tracked have no company_id
+	if _, err := db.Exec(`INSERT INTO jobs (id, title, company, job_url) VALUES ('legacy', 'Legacy Job', 'Gone Inc', 'https://gone.example/1')`); err != nil {
+		t.Fatalf("inserting job: %v", err)
+	}
+
+	jobs := allJobs(t, store)
+	if len(jobs) != 1 || jobs[0].ID != "legacy" {
+		t.Fatalf("jobs = %v, want the legacy job", titles(jobs))
+	}
+	if jobs[0].Company != nil {
+		t.Errorf("company = %+v, want none", jobs[0].Company)
+	}
+
+	job, err := store.GetJobByID("legacy")
+	if err != nil {
+		t.Fatalf("getting job: %v", err)
+	}
+	if job.Title != "Legacy Job" || job.Company != nil {
+		t.Errorf("job = %+v, want the legacy job without company", job)
+	}
+}
+
+func testSearchJobsOrder(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saveJobs(t, store, companyID,
+		scraping.Job{Title: "Undated", Url: "https://acme.example/jobs/1"},
+		scraping.Job{Title: "Oldest", Url: "https://acme.example/jobs/2", PublishedAt: "2023-12-31T23:00:00Z"},
+		scraping.Job{Title: "Newest", Url: "https://acme.example/jobs/3", PublishedAt: "2024-06-01T08:00:00Z"},
+		scraping.Job{Title: "Middle", Url: "https://acme.example/jobs/4", PublishedAt: "2024-01-15T12:00:00Z"},
+	)
+
+	jobs, err := store.SearchJobs(storage.JobFilter{})
+	if err != nil {
+		t.Fatalf("searching jobs: %v", err)
+	}
+	if got, want := titles(jobs), []string{"Newest", "Middle", "Oldest", "Undated"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func testSearchJobsLeavesOutDuplicates(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saveJobs(t, store, companyID,
+		scraping.Job{Title: "Engineer", Url: "https://acme.example/jobs/1"},
+		scraping.Job{Title: "Engineer", Url: "https://acme.example/jobs/2"},
+	)
+	jobs := allJobs(t, store)
+	if err := store.SetCanonicalJobs(map[string]string{jobs[1].ID: jobs[0].ID}); err != nil {
+		t.Fatalf("setting canonical jobs: %v", err)
+	}
+
+	listed, err := store.SearchJobs(storage.JobFilter{Query: "engineer"})
+	if err != nil {
+		t.Fatalf("searching jobs: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != jobs[0].ID {
+		t.Errorf("listed %d jobs, want only the canonical one", len(listed))
+	}
+}
+
+func testSearchJobsFilters(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	saveJobs(t, store, companyID,
+		scraping.Job{Title: "Go Engineer", Url: "https://acme.example/jobs/1", Tags: []string{"go"}, Language: "en", Timezone: &scraping.TimezoneWindow{Min: -480, Max: -300}},
+		scraping.Job{Title: "Data Engineer", Url: "https://acme.example/jobs/2", Language: "de", Timezone: &scraping.TimezoneWindow{Min: 60, Max: 60}},
+		scraping.Job{Title: "Designer", Url: "https://acme.example/jobs/3", EmploymentType: scraping.EmploymentPartTime},
+	)
+	cet := 60
+
+	tests := []struct {
+		name   string
+		filter storage.JobFilter
+		want   []string
+	}{
+		{"query ignores case", storage.JobFilter{Query: "ENGINEER"}, []string{"Go Engineer", "Data Engineer"}},
+		{"query matches word prefixes", storage.JobFilter{Query: "eng"}, []string{"Go Engineer", "Data Engineer"}},
+		{"tag", storage.JobFilter{Tag: "go"}, []string{"Go Engineer"}},
+		{"employment type", storage.JobFilter{EmploymentTypes: []string{scraping.EmploymentPartTime}}, []string{"Designer"}},
+		{"languages keep undetected ones", storage.JobFilter{Languages: []string{"de"}}, []string{"Data Engineer", "Designer"}},
+		{"timezone overlap", storage.JobFilter{UTCOffset: &cet, OverlapHours: 4}, []string{"Data Engineer", "Designer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := store.SearchJobs(tt.filter)
+			if err != nil {
+				t.Fatalf("searching jobs: %v", err)
+			}
+			got := titles(jobs)
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func testApproveSubmission(t *testing.T, newStore NewStoreFunc) {
+	_, store, _ := setup(t, newStore)
+	submissionID, err := store.SaveSubmission(storage.CompanySubmission{Name: "Globex", CareersURL: "https://globex.example/careers"})
+	if err != nil {
+		t.Fatalf("saving submission: %v", err)
+	}
+	company := scraping.Company{Name: "Globex", CareersURL: "https://globex.example/careers", ATSType: "lever", ATSUrl: "https://api.lever.co/v0/postings/globex"}
+
+	companyID, err := store.ApproveSubmission(submissionID, company)
+	if err != nil {
+		t.Fatalf("approving submission: %v", err)
+	}
+	submission, err := store.GetSubmissionByID(submissionID)
+	if err != nil {
+		t.Fatalf("getting submission: %v", err)
+	}
+	if submission.Status != storage.SubmissionApproved || submission.CompanyID != companyID {
+		t.Errorf("submission = %s with company %d, want approved with %d", submission.Status, submission.CompanyID, companyID)
+	}
+
+	// Approving again saves no second company
+	if _, err := store.ApproveSubmission(submissionID, company); !errors.Is(err, storage.ErrSubmissionModerated) {
+		t.Errorf("approving twice: got error %v, want ErrSubmissionModerated", err)
+	}
+	companies, err := store.GetCompanies()
+	if err != nil {
+		t.Fatalf("getting companies: %v", err)
+	}
+	if len(companies) != 2 {
+		t.Errorf("got %d companies, want Acme and Globex", len(companies))
+	}
+}
+
+func testRejectSubmission(t *testing.T, newStore NewStoreFunc) {
+	_, store, _ := setup(t, newStore)
+	submitted := storage.CompanySubmission{Name: "Globex", CareersURL: "https://globex.example/careers", SubmitterEmail: "jane@globex.example"}
+	submissionID, err := store.SaveSubmission(submitted)
+	if err != nil {
+		t.Fatalf("saving submission: %v", err)
+	}
+
+	pending, err := store.GetSubmissionsByStatus(storage.SubmissionPending)
+	if err != nil {
+		t.Fatalf("getting submissions: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != submissionID || pending[0].SubmitterEmail != submitted.SubmitterEmail {
+		t.Fatalf("pending = %+v, want the saved submission", pending)
+	}
+
+	if err := store.SetSubmissionStatus(submissionID, storage.SubmissionRejected, 0); err != nil {
+		t.Fatalf("rejecting submission: %v", err)
+	}
+	rejected, err := store.GetSubmissionByID(submissionID)
+	if err != nil {
+		t.Fatalf("getting submission: %v", err)
+	}
+	if rejected.Status != storage.SubmissionRejected || rejected.CompanyID != 0 {
+		t.Errorf("submission = %s with company %d, want rejected without company", rejected.Status, rejected.CompanyID)
+	}
+	if _, err := store.GetSubmissionByID(submissionID + 1); !errors.Is(err, storage.ErrSubmissionNotFound) {
+		t.Errorf("unknown submission: got error %v, want ErrSubmissionNotFound", err)
+	}
+}
+
+func testBoardStates(t *testing.T, newStore NewStoreFunc) {
+	_, store, companyID := setup(t, newStore)
+	state, err := store.GetBoardState(companyID)
+	if err != nil {
+		t.Fatalf("getting board state: %v", err)
+	}
+	if state != (scraping.BoardState{}) {
+		t.Errorf("state = %+v before any scrape, want none", state)
+	}
+
+	for _, saved := range []scraping.BoardState{
+		{URL: "https://api.ashbyhq.com/posting-api/job-board/acme", ETag: `"v1"`, LastModified: "Wed, 01 May 2024 10:00:00 GMT", ContentHash: "abc"},
+		{URL: "https://api.ashbyhq.com/posting-api/job-board/acme", ContentHash: "def"},
+	} {
+		if err := store.SaveBoardState(companyID, saved); err != nil {
+			t.Fatalf("saving board state: %v", err)
+		}
+		state, err := store.GetBoardState(companyID)
+		if err != nil {
+			t.Fatalf("getting board state: %v", err)
+		}
+		if state != saved {
+			t.Errorf("state = %+v, want %+v", state, saved)
+		}
+	}
+}
+
+func jobIDByURL(t *testing.T, jobs []scraping.Job, url string) string {
+	t.Helper()
+	for _, job := range jobs {
+		if job.Url == url {
+			return job.ID
+		}
+	}
+	t.Fatalf("no job at %s", url)
+	return ""
+}
@@ -0,0 +1,82 @@
+package storage
+
+import "github.com/ddahon/workfromearth/internal/scraping"
+
+// CompanyStore persists the companies whose careers pages are scraped
+type CompanyStore interface {
+	GetCompanies() ([]scraping.Company, error)
+	GetCompanyByID(id int64) (*scraping.Company, error)
+	GetCompanyByCareersURL(careersURL string) (*scraping.Company, error)
+	GetCompanyByURL(url string) (*scraping.Company, error)
+	// SaveCompany inserts the company when its ID is 0, updates it otherwise,
+	// and returns its ID
+	SaveCompany(company scraping.Company) (int64, error)
+	// SaveCompanies saves companies like SaveCompany, all of them or none
+	SaveCompanies(companies []scraping.Company) ([]int64, error)
+	UpdateScrapedAt(companyID int64) error
+	// GetBoardState and SaveBoardState keep what makes the next scrape of a
+	// company's board conditional
+	GetBoardState(companyID int64) (scraping.BoardState, error)
+	SaveBoardState(companyID int64, state scraping.BoardState) error
+	SetCompanyDisabled(companyID int64, disabled bool) error
+	DeleteCompany(companyID int64) error
+}
+
+// JobStore persists the scraped jobs, the revisions recorded when a scrape
+// changes them and the links between duplicate jobs
+type JobStore interface {
+	SaveJobs(jobs []scraping.Job, companyID int64) error
+	GetAllJobs() ([]scraping.Job, error)
+	// SearchJobs returns the jobs to list matching filter
+	SearchJobs(filter JobFilter) ([]scraping.Job, error)
+	// GetJobDepartments returns the departments of the listed jobs, sorted
+	GetJobDepartments() ([]string, error)
+	GetJobByID(id string) (*scraping.Job, error)
+	GetJobRevisions(jobID string) ([]JobRevision, error)
+	GetJobDuplicates(jobID string) ([]scraping.Job, error)
+	GetJobsForDedup() ([]scraping.Job, error)
+	SetCanonicalJobs(duplicates map[string]string) error
+}
+
+// SubmissionStore persists the companies suggested by visitors
+type SubmissionStore interface {
+	SaveSubmission(s CompanySubmission) (int64, error)
+	GetSubmissionsByStatus(status string) ([]CompanySubmission, error)
+	GetSubmissionByID(id int64) (*CompanySubmission, error)
+	SetSubmissionStatus(id int64, status string, companyID int64) error
+	// ApproveSubmission saves the company reviewed from a pending submission
+	// and marks the submission as approved with it, both or neither
+	ApproveSubmission(id int64, company scraping.Company) (int64, error)
+}
+
+// Store is everything the site, the scraper and the CLI read and write,
+// implemented by the SQLite Repository and by the postgres package
+type Store interface {
+	CompanyStore
+	JobStore
+	SubmissionStore
+	Stats() (Stats, error)
+}
+
+// Migrator manages the schema of a database
+type Migrator interface {
+	// Migrations returns the migrations embedded for this backend, sorted by version
+	Migrations() ([]Migration, error)
+	AppliedMigrations() ([]AppliedMigration, error)
+	Migrate(migrations []Migration) ([]Migration, error)
+	MigrateDown(migrations []Migration, steps int) ([]Migration, error)
+	Baseline(migrations []Migration, version string) error
+}
+
+// Database is an open connection to one of the storage backends
+type Database interface {
+	Migrator
+	// Store returns the Store backed by this database
+	Store() Store
+	Close() error
+}
+
+var (
+	_ Store    = (*Repository)(nil)
+	_ Database = (*DB)(nil)
+)
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+)
+
+const (
+	SubmissionPending  = "pending"
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+)
+
+// ErrSubmissionNotFound is returned when a submission lookup matches no row
+var ErrSubmissionNotFound = errors.New("no submission found")
+
+// ErrSubmissionModerated is returned when approving a submission which is no
+// longer pending
+var ErrSubmissionModerated = errors.New("submission already moderated")
+
+// CompanySubmission is a company suggested by a visitor, pending moderation
+type CompanySubmission struct {
+	ID             int64
+	Name           string
+	SiteURL        string
+	CareersURL     string
+	ATSType        string
+	ATSUrl         string
+	SubmitterEmail string
+	Notes          string
+	Status         string
+	CompanyID      int64
+	CreatedAt      string
+	UpdatedAt      string
+}
+
+const submissionColumns = `id, name, site_url, careers_url, ats_type, ats_url, submitter_email, notes, status, company_id, created_at, updated_at`
+
+func scanSubmission(row rowScanner) (*CompanySubmission, error) {
+	var s CompanySubmission
+	var name, siteURL, atsType, atsURL, email, notes sql.NullString
+	var companyID sql.NullInt64
+
+	err := row.Scan(
+		&s.ID,
+		&name,
+		&siteURL,
+		&s.CareersURL,
+		&atsType,
+		&atsURL,
+		&email,
+		&notes,
+		&s.Status,
+		&companyID,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Name = name.String
+	s.SiteURL = siteURL.String
+	s.ATSType = atsType.String
+	s.ATSUrl = atsURL.String
+	s.SubmitterEmail = email.String
+	s.Notes = notes.String
+	s.CompanyID = companyID.Int64
+
+	return &s, nil
+}
+
+func (r *Repository) SaveSubmission(s CompanySubmission) (int64, error) {
+	query := `
+		INSERT INTO company_submissions (name, site_url, careers_url, ats_type, ats_url, submitter_email, notes, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	result, err := r.db.Exec(query, s.Name, s.SiteURL, s.CareersURL, s.ATSType, s.ATSUrl, s.SubmitterEmail, s.Notes, SubmissionPending)
+	if err != nil {
+		return 0, fmt.Errorf("saving submission: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("getting last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// GetSubmissionsByStatus returns submissions with the given status, oldest first
+func (r *Repository) GetSubmissionsByStatus(status string) ([]CompanySubmission, error) {
+	query := `SELECT ` + submissionColumns + ` FROM company_submissions WHERE status = $1 ORDER BY created_at, id`
+	rows, err := r.db.reader.Query(query, status)
+	if err != nil {
+		return nil, fmt.Errorf("querying submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var submissions []CompanySubmission
+	for rows.Next() {
+		s, err := scanSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning submission: %w", err)
+		}
+		submissions = append(submissions, *s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+func (r *Repository) GetSubmissionByID(id int64) (*CompanySubmission, error) {
+	query := `SELECT ` + submissionColumns + ` FROM company_submissions WHERE id = $1`
+	s, err := scanSubmission(r.db.reader.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w with ID: %d", ErrSubmissionNotFound, id)
+		}
+		return nil, fmt.Errorf("scanning submission: %w", err)
+	}
+	return s, nil
+}
+
+// SetSubmissionStatus records the moderation decision for a submission.
+// companyID links an approved submission to the company created from it.
+func (r *Repository) SetSubmissionStatus(id int64, status string, companyID int64) error {
+	query := `UPDATE company_submissions SET status = $1, company_id = NULLIF($2, 0), updated_at = datetime('now') WHERE id = $3`
+	if _, err := r.db.Exec(query, status, companyID, id); err != nil {
+		return fmt.Errorf("updating submission status: %w", err)
+	}
+	return nil
+}
+
+// ApproveSubmission saves the company reviewed from a pending submission and
+// marks the submission as approved with it, in one transaction so neither
+// happens without the other. It returns the ID of the company.
+func (r *Repository) ApproveSubmission(id int64, company scraping.Company) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	companyID, err := saveCompany(tx, company)
+	if err != nil {
+		return 0, err
+	}
+	query := `UPDATE company_submissions SET status = $1, company_id = $2, updated_at = datetime('now') WHERE id = $3 AND status = $4`
+	result, err := tx.Exec(query, SubmissionApproved, companyID, id, SubmissionPending)
+	if err != nil {
+		return 0, fmt.Errorf("updating submission status: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("getting affected rows: %w", err)
+	}
+	if updated == 0 {
+		return 0, fmt.Errorf("%w with ID: %d", ErrSubmissionModerated, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+	return companyID, nil
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SplitColumn parses a comma-separated column, such as the tags of a company
+// or the tags and signals aggregated for a job
+func SplitColumn(s string) []string {
+	var values []string
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// SaveJobTags replaces the skill tags of a job. It runs in the transaction
+// saving the job, and its SQL is shared by both backends.
+func SaveJobTags(tx *sql.Tx, jobID string, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM job_tags WHERE job_id = $1`, jobID); err != nil {
+		return fmt.Errorf("deleting job tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT INTO job_tags (job_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, jobID, tag); err != nil {
+			return fmt.Errorf("saving job tag: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package web
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+	"github.com/ddahon/workfromearth/internal/web/views"
+)
+
+type adminHandler struct {
+	repo storage.Store
+}
+
+// registerAdminRoutes mounts the company management pages under /admin,
+// protected by HTTP basic auth against the configured bcrypt password hash
+func registerAdminRoutes(mux *http.ServeMux, repo storage.Store, username, passwordHash string) {
+	h := adminHandler{repo: repo}
+
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /admin", h.listCompanies)
+	admin.HandleFunc("GET /admin/companies/new", h.newCompany)
+	admin.HandleFunc("POST /admin/companies", h.saveCompany)
+	admin.HandleFunc("GET /admin/companies/{id}", h.editCompany)
+	admin.HandleFunc("POST /admin/companies/{id}", h.saveCompany)
+	admin.HandleFunc("POST /admin/companies/{id}/scrape", h.previewCompany)
+	admin.HandleFunc("POST /admin/companies/{id}/disable", h.setDisabled(true))
+	admin.HandleFunc("POST /admin/companies/{id}/enable", h.setDisabled(false))
+	admin.HandleFunc("POST /admin/companies/{id}/delete", h.deleteCompany)
+	admin.HandleFunc("GET /admin/submissions", h.listSubmissions)
+	admin.HandleFunc("GET /admin/submissions/{id}", h.reviewSubmission)
+	admin.HandleFunc("POST /admin/submissions/{id}/approve", h.approveSubmission)
+	admin.HandleFunc("POST /admin/submissions/{id}/reject", h.rejectSubmission)
+
+	protected := requireAdmin(username, passwordHash, http.NewCrossOriginProtection().Handler(admin))
+	mux.Handle("/admin", protected)
+	mux.Handle("/admin/", protected)
+}
+
+func requireAdmin(username, passwordHash string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(username)) != 1 ||
+			bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) != nil {
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin", charset="UTF-8"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h adminHandler) listCompanies(w http.ResponseWriter, r *http.Request) {
+	companies, err := h.repo.GetCompanies()
+	if err != nil {
+		log.Printf("Failed to retrieve companies from DB: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	render(w, r, views.AdminCompanies(companies))
+}
+
+func (h adminHandler) newCompany(w http.ResponseWriter, r *http.Request) {
+	render(w, r, views.AdminCompanyForm(views.CompanyFormData{}))
+}
+
+func (h adminHandler) editCompany(w http.ResponseWriter, r *http.Request) {
+	company, ok := h.lookupCompany(w, r)
+	if !ok {
+		return
+	}
+	render(w, r, views.AdminCompanyForm(views.CompanyFormData{Company: *company}))
+}
+
+// saveCompany creates or updates a company from the submitted form. When the
+// form is submitted with action=preview, the company is scraped without being
+// saved so the returned jobs can be reviewed first.
+func (h adminHandler) saveCompany(w http.ResponseWriter, r *http.Request) {
+	var company scraping.Company
+	if r.PathValue("id") != "" {
+		existing, ok := h.lookupCompany(w, r)
+		if !ok {
+			return
+		}
+		company = *existing
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	company = companyFromForm(r, company)
+
+	data := views.CompanyFormData{Company: company}
+	if err := scraping.ValidateCompany(company); err != nil {
+		data.Error = err.Error()
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, r, views.AdminCompanyForm(data))
+		return
+	}
+
+	if r.PostForm.Get("action") == "preview" {
+		render(w, r, views.AdminCompanyForm(dryRunScrape(data)))
+		return
+	}
+
+	if _, err := h.repo.SaveCompany(company); err != nil {
+		log.Printf("Failed to save company %s: %v", company.Name, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+func (h adminHandler) previewCompany(w http.ResponseWriter, r *http.Request) {
+	company, ok := h.lookupCompany(w, r)
+	if !ok {
+		return
+	}
+	render(w, r, views.AdminCompanyForm(dryRunScrape(views.CompanyFormData{Company: *company})))
+}
+
+func (h adminHandler) setDisabled(disabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		company, ok := h.lookupCompany(w, r)
+		if !ok {
+			return
+		}
+		if err := h.repo.SetCompanyDisabled(company.ID, disabled); err != nil {
+			log.Printf("Failed to update company %s: %v", company.Name, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	}
+}
+
+func (h adminHandler) deleteCompany(w http.ResponseWriter, r *http.Request) {
+	company, ok := h.lookupCompany(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.DeleteCompany(company.ID); err != nil {
+		log.Printf("Failed to delete company %s: %v", company.Name, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+// lookupCompany loads the company referenced by the {id} path segment,
+// writing an error response and returning false if it cannot be found
+func (h adminHandler) lookupCompany(w http.ResponseWriter, r *http.Request) (*scraping.Company, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return nil, false
+	}
+
+	company, err := h.repo.GetCompanyByID(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrCompanyNotFound) {
+			http.NotFound(w, r)
+		} else {
+			log.Printf("Failed to retrieve company %d: %v", id, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return company, true
+}
+
+func (h adminHandler) listSubmissions(w http.ResponseWriter, r *http.Request) {
+	submissions, err := h.repo.GetSubmissionsByStatus(storage.SubmissionPending)
+	if err != nil {
+		log.Printf("Failed to retrieve submissions from DB: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	render(w, r, views.AdminSubmissions(submissions))
+}
+
+// reviewSubmission shows a pending submission as a prefilled company form,
+// detecting the ATS from the careers page if the submitted URL did not
+// reveal it, along with a preview scrape when an ATS was found
+func (h adminHandler) reviewSubmission(w http.ResponseWriter, r *http.Request) {
+	submission, ok := h.lookupPendingSubmission(w, r)
+	if !ok {
+		return
+	}
+
+	data := views.CompanyFormData{
+		Company: scraping.Company{
+			Name:       submission.Name,
+			SiteURL:    submission.SiteURL,
+			CareersURL: submission.CareersURL,
+			ATSType:    submission.ATSType,
+			ATSUrl:     submission.ATSUrl,
+		},
+		Submission: submission,
+	}
+	if data.Company.ATSType == "" {
+		if detected, err := scraping.DetectATS(submission.CareersURL); err == nil {
+			data.Company.ATSType = detected.Type
+			data.Company.ATSUrl = detected.URL
+		}
+	}
+	if data.Company.ATSType != "" {
+		data = dryRunScrape(data)
+	}
+	render(w, r, views.AdminCompanyForm(data))
+}
+
+// approveSubmission creates a company from the reviewed form and marks the
+// submission as approved. action=preview only rescrapes the edited values.
+func (h adminHandler) approveSubmission(w http.ResponseWriter, r *http.Request) {
+	submission, ok := h.lookupPendingSubmission(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	company := companyFromForm(r, scraping.Company{})
+
+	data := views.CompanyFormData{Company: company, Submission: submission}
+	if err := scraping.ValidateCompany(company); err != nil {
+		data.Error = err.Error()
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, r, views.AdminCompanyForm(data))
+		return
+	}
+
+	if r.PostForm.Get("action") == "preview" {
+		render(w, r, views.AdminCompanyForm(dryRunScrape(data)))
+		return
+	}
+
+	if _, err := h.repo.ApproveSubmission(submission.ID, company); err != nil {
+		if errors.Is(err, storage.ErrSubmissionModerated) {
+			http.Error(w, "Submission was already moderated", http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to approve submission %d: %v", submission.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin/submissions", http.StatusSeeOther)
+}
+
+func (h adminHandler) rejectSubmission(w http.ResponseWriter, r *http.Request) {
+	submission, ok := h.lookupPendingSubmission(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.SetSubmissionStatus(submission.ID, storage.SubmissionRejected, 0); err != nil {
+		log.Printf("Failed to reject submission %d: %v", submission.ID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin/submissions", http.StatusSeeOther)
+}
+
+// lookupPendingSubmission loads the submission referenced by the {id} path
+// segment, writing an error response and returning false if it cannot be
+// found or has already been moderated
+func (h adminHandler) lookupPendingSubmission(w http.ResponseWriter, r *http.Request) (*storage.CompanySubmission, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return nil, false
+	}
+
+	submission, err := h.repo.GetSubmissionByID(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrSubmissionNotFound) {
+			http.NotFound(w, r)
+		} else {
+			log.Printf("Failed to retrieve submission %d: %v", id, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if submission.Status != storage.SubmissionPending {
+		http.Error(w, fmt.Sprintf("Submission was already %s", submission.Status), http.StatusConflict)
+		return nil, false
+	}
+	return submission, true
+}
+
+// companyFromForm overwrites the editable fields of company with the submitted form values
+func companyFromForm(r *http.Request, company scraping.Company) scraping.Company {
+	company.Name = strings.TrimSpace(r.PostForm.Get("name"))
+	company.SiteURL = strings.TrimSpace(r.PostForm.Get("siteurl"))
+	company.CareersURL = strings.TrimSpace(r.PostForm.Get("careersurl"))
+	company.ATSType = strings.TrimSpace(r.PostForm.Get("atstype"))
+	company.ATSUrl = strings.TrimSpace(r.PostForm.Get("atsurl"))
+	company.Tags = scraping.SplitTags(r.PostForm.Get("tags"))
+	return company
+}
+
+// dryRunScrape scrapes the form's company and attaches the result without saving anything
+func dryRunScrape(data views.CompanyFormData) views.CompanyFormData {
+	data.Previewed = true
+
+	jobs, err := scraping.PreviewScrape(data.Company)
+	if err != nil {
+		data.Error = fmt.Sprintf("scraping failed: %v", err)
+		return data
+	}
+	data.Jobs = jobs
+	return data
+}
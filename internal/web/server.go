@@ -0,0 +1,138 @@
+// Package web serves the public job board, the company suggestion form and
+// the admin area.
+package web
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/a-h/templ"
+
+	"github.com/ddahon/workfromearth/internal/config"
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+	"github.com/ddahon/workfromearth/internal/web/views"
+)
+
+// NewHandler returns the handler serving every page of the site
+func NewHandler(repo storage.Store, admin config.AdminConfig) http.Handler {
+	mux := http.NewServeMux()
+
+	// listJobs renders the index page with the jobs matching filter
+	listJobs := func(w http.ResponseWriter, r *http.Request, filter storage.JobFilter) {
+		jobs, err := repo.SearchJobs(filter)
+		if err != nil {
+			log.Printf("Failed to retrieve jobs from DB: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		departments, err := repo.GetJobDepartments()
+		if err != nil {
+			log.Printf("Failed to retrieve departments from DB: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		render(w, r, views.Index(jobs, filter, departments))
+	}
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		listJobs(w, r, jobFilter(r.URL.Query()))
+	})
+
+	mux.HandleFunc("GET /tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		skill, ok := scraping.LookupSkill(r.PathValue("tag"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		filter := jobFilter(r.URL.Query())
+		filter.Tag = skill.Tag
+		listJobs(w, r, filter)
+	})
+
+	mux.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		job, err := repo.GetJobByID(r.PathValue("id"))
+		if errors.Is(err, storage.ErrJobNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to retrieve job from DB: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		revisions, err := repo.GetJobRevisions(job.ID)
+		if err != nil {
+			log.Printf("Failed to retrieve job revisions from DB: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		duplicates, err := repo.GetJobDuplicates(job.ID)
+		if err != nil {
+			log.Printf("Failed to retrieve duplicate jobs from DB: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		render(w, r, views.JobDetail(*job, revisions, duplicates))
+	})
+
+	registerSuggestRoutes(mux, repo)
+
+	if admin.PasswordHash != "" {
+		registerAdminRoutes(mux, repo, admin.Username, admin.PasswordHash)
+	} else {
+		log.Printf("admin.passwordHash is not set, admin area is disabled")
+	}
+
+	return mux
+}
+
+func render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		log.Printf("Failed to respond to request: %v", err)
+	}
+}
+
+// defaultOverlapHours is the working day overlap asked for until users pick one
+const defaultOverlapHours = 4
+
+// jobFilter reads the index page filters from the query string, ignoring
+// unknown employment types and languages and out of range timezones
+func jobFilter(query url.Values) storage.JobFilter {
+	filter := storage.JobFilter{
+		Query:      query.Get("q"),
+		Department: query.Get("department"),
+		Seniority:  query.Get("seniority"),
+		Category:   query.Get("category"),
+		// Unchecked checkboxes aren't sent, so any value hides restricted jobs
+		HideRestricted: query.Get("unrestricted") != "",
+	}
+	for _, employmentType := range query["type"] {
+		if slices.Contains(scraping.EmploymentTypes, employmentType) {
+			filter.EmploymentTypes = append(filter.EmploymentTypes, employmentType)
+		}
+	}
+	for _, language := range query["lang"] {
+		if slices.ContainsFunc(scraping.Languages, func(l scraping.Language) bool { return l.Code == language }) {
+			filter.Languages = append(filter.Languages, language)
+		}
+	}
+	filter.OverlapHours = defaultOverlapHours
+	if hours, err := strconv.Atoi(query.Get("overlap")); err == nil && hours >= 1 && hours <= scraping.WorkdayMinutes/60 {
+		filter.OverlapHours = hours
+	}
+	// Offsets run from UTC−12 to UTC+14
+	if offset, err := strconv.Atoi(query.Get("tz")); err == nil && offset >= -12*60 && offset <= 14*60 {
+		filter.UTCOffset = &offset
+	}
+	return filter
+}
@@ -0,0 +1,87 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+	"github.com/ddahon/workfromearth/internal/storage"
+	"github.com/ddahon/workfromearth/internal/web/views"
+)
+
+const maxSubmissionFieldLength = 2000
+
+type suggestHandler struct {
+	repo storage.Store
+}
+
+// registerSuggestRoutes mounts the public form where visitors can propose a
+// company. Submissions land in the admin moderation queue.
+func registerSuggestRoutes(mux *http.ServeMux, repo storage.Store) {
+	h := suggestHandler{repo: repo}
+	mux.HandleFunc("GET /suggest", h.form)
+	mux.Handle("POST /suggest", http.NewCrossOriginProtection().Handler(http.HandlerFunc(h.submit)))
+}
+
+func (h suggestHandler) form(w http.ResponseWriter, r *http.Request) {
+	render(w, r, views.Suggest(views.SuggestFormData{}))
+}
+
+func (h suggestHandler) submit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	// Bots fill every field, humans never see this one
+	if r.PostForm.Get("website") != "" {
+		render(w, r, views.SuggestThanks())
+		return
+	}
+
+	submission := storage.CompanySubmission{
+		Name:           formValue(r, "name"),
+		SiteURL:        formValue(r, "siteurl"),
+		CareersURL:     formValue(r, "careersurl"),
+		SubmitterEmail: formValue(r, "email"),
+		Notes:          formValue(r, "notes"),
+	}
+
+	if !isHTTPURL(submission.CareersURL) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		render(w, r, views.Suggest(views.SuggestFormData{
+			Submission: submission,
+			Error:      "Please enter the full URL of the company's careers page.",
+		}))
+		return
+	}
+
+	// Only match the URL here, fetching the page is left to the admin review
+	if detected, ok := scraping.DetectATSFromURL(submission.CareersURL); ok {
+		submission.ATSType = detected.Type
+		submission.ATSUrl = detected.URL
+	}
+
+	if _, err := h.repo.SaveSubmission(submission); err != nil {
+		log.Printf("Failed to save submission for %s: %v", submission.CareersURL, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	render(w, r, views.SuggestThanks())
+}
+
+func formValue(r *http.Request, key string) string {
+	value := strings.TrimSpace(r.PostForm.Get(key))
+	if len(value) > maxSubmissionFieldLength {
+		value = value[:maxSubmissionFieldLength]
+	}
+	return value
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
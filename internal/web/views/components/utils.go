@@ -0,0 +1,93 @@
+package components
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+
+	"github.com/ddahon/workfromearth/internal/scraping"
+)
+
+var descriptionPolicy = bluemonday.UGCPolicy()
+
+// SanitizeDescription returns the job description HTML from the ATS without
+// scripts, styles or event handlers. Greenhouse sends it entity-escaped.
+func SanitizeDescription(description string) string {
+	if !strings.Contains(description, "<") && strings.Contains(description, "&lt;") {
+		description = html.UnescapeString(description)
+	}
+	return descriptionPolicy.Sanitize(description)
+}
+
+// EmploymentTypeLabel capitalizes a normalized employment type for display,
+// e.g. "Full-time"
+func EmploymentTypeLabel(employmentType string) string {
+	if employmentType == "" {
+		return ""
+	}
+	return strings.ToUpper(employmentType[:1]) + employmentType[1:]
+}
+
+// TagLabel returns the label of a classification tag, or the tag itself if
+// the rules no longer define it
+func TagLabel(tags []scraping.ClassificationTag, tag string) string {
+	for _, t := range tags {
+		if t.Tag == tag {
+			return t.Label
+		}
+	}
+	return tag
+}
+
+// SkillName returns the display name of a skill tag, or the tag itself if
+// the dictionary no longer defines it
+func SkillName(tag string) string {
+	if skill, ok := scraping.LookupSkill(tag); ok {
+		return skill.Name
+	}
+	return tag
+}
+
+// FormatRelativeDate formats a published date, stored as UTC RFC3339, as a
+// relative time (e.g., "3d ago", "2h ago")
+// Returns empty string if the date cannot be parsed
+func FormatRelativeDate(dateStr string) string {
+	return formatRelativeDate(dateStr, time.Now())
+}
+
+// formatRelativeDate is FormatRelativeDate relative to now
+func formatRelativeDate(dateStr string, now time.Time) string {
+	if dateStr == "" {
+		return ""
+	}
+
+	t, err := time.Parse(time.RFC3339, dateStr)
+	if err != nil {
+		return ""
+	}
+
+	duration := now.Sub(t)
+
+	if duration < 0 {
+		return ""
+	}
+
+	hours := duration.Hours()
+	days := hours / 24
+
+	switch {
+	case hours < 1:
+		minutes := duration.Minutes()
+		if minutes < 1 {
+			return "just now"
+		}
+		return fmt.Sprintf("%.0fm ago", minutes)
+	case hours < 24:
+		return fmt.Sprintf("%.0fh ago", hours)
+	default:
+		return fmt.Sprintf("%.0fd ago", days)
+	}
+}
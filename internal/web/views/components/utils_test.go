@@ -0,0 +1,44 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzFormatRelativeDate(f *testing.F) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	// Dates as stored once normalized, and as ATSs report them
+	for _, seed := range []string{
+		"2024-05-01T10:00:00Z",
+		"2024-03-15T00:00:00.000Z",
+		"2024-05-01T10:00:00.123+02:00",
+		"2024-06-01T09:00:00-04:00",
+		"2024-07-01 08:00:00 UTC",
+		"1714557600000",
+		now.Add(-90 * time.Minute).Format(time.RFC3339),
+		now.Add(-59 * time.Second).Format(time.RFC3339),
+		now.Add(time.Hour).Format(time.RFC3339),
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, dateStr string) {
+		formatted := formatRelativeDate(dateStr, now)
+
+		published, err := time.Parse(time.RFC3339, dateStr)
+		if err != nil || published.After(now) {
+			if formatted != "" {
+				t.Errorf("date %q formatted as %q, want nothing", dateStr, formatted)
+			}
+			return
+		}
+		if formatted != "just now" && !strings.HasSuffix(formatted, " ago") {
+			t.Errorf("past date %q formatted as %q", dateStr, formatted)
+		}
+		if age := now.Sub(published); age < time.Minute && formatted != "just now" {
+			t.Errorf("date %q, %v old, formatted as %q, want just now", dateStr, age, formatted)
+		}
+	})
+}